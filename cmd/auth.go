@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage project authentication",
+}
+
+var authSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the authentication used for a project's routes",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		typeStr, _ := cmd.Flags().GetString("type")
+		token, _ := cmd.Flags().GetString("token")
+		username, _ := cmd.Flags().GetString("username")
+		password, _ := cmd.Flags().GetString("password")
+		keyName, _ := cmd.Flags().GetString("key-name")
+		keyValue, _ := cmd.Flags().GetString("key-value")
+		keyInStr, _ := cmd.Flags().GetString("key-in")
+		loginRouteName, _ := cmd.Flags().GetString("login-route")
+		tokenPath, _ := cmd.Flags().GetString("token-path")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		authType, err := auth.ParseType(typeStr)
+		if err != nil {
+			return err
+		}
+		keyIn, err := auth.ParseLocation(keyInStr)
+		if err != nil {
+			return err
+		}
+		a := &auth.Profile{
+			ProjectID: p.ID,
+			Type:      authType,
+			Token:     token,
+			Username:  username,
+			Password:  password,
+			KeyName:   keyName,
+			KeyValue:  keyValue,
+		}
+		if authType == auth.APIKey {
+			a.KeyIn = keyIn
+		}
+		if authType == auth.JWT {
+			if loginRouteName == "" {
+				return fmt.Errorf("jwt auth requires --login-route")
+			}
+			loginRoute, err := storage.GetRouteByName(p.ID, loginRouteName)
+			if err != nil {
+				return fmt.Errorf("login route '%s' not found in project '%s': %w", loginRouteName, p.Name, err)
+			}
+			a.LoginRouteID = loginRoute.ID
+			a.TokenPath = tokenPath
+			if token != "" {
+				if a.TokenExpiry, err = auth.ParseExpiry(token); err != nil {
+					return fmt.Errorf("invalid token: %w", err)
+				}
+			}
+		}
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if err := storage.SetAuth(a); err != nil {
+			return fmt.Errorf("failed to save auth: %w", err)
+		}
+		fmt.Printf("Project '%s' now uses %s\n", p.Name, a.Summary())
+		return nil
+	},
+}
+
+var authShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the authentication configured for a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		a, err := storage.GetAuth(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load auth: %w", err)
+		}
+		if a == nil {
+			fmt.Printf("No auth set for project '%s'\n", p.Name)
+			return nil
+		}
+		fmt.Printf("Project '%s' uses %s\n", p.Name, a.Summary())
+		return nil
+	},
+}
+
+var authClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove the authentication from a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		if err := storage.ClearAuth(p.ID); err != nil {
+			return fmt.Errorf("failed to clear auth: %w", err)
+		}
+		fmt.Printf("Auth cleared for project '%s'\n", p.Name)
+		return nil
+	},
+}
+
+func init() {
+	projectCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authSetCmd)
+	authCmd.AddCommand(authShowCmd)
+	authCmd.AddCommand(authClearCmd)
+
+	// Set command flags
+	authSetCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := authSetCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	authSetCmd.Flags().StringP("type", "t", "", "Auth type: bearer, basic, apikey, jwt (required)")
+	if err := authSetCmd.MarkFlagRequired("type"); err != nil {
+		panic(err)
+	}
+	authSetCmd.Flags().String("token", "", "Bearer token, or an initial token for jwt auth")
+	authSetCmd.Flags().String("username", "", "Basic auth username")
+	authSetCmd.Flags().String("password", "", "Basic auth password")
+	authSetCmd.Flags().String("key-name", "", "API key header or query parameter name")
+	authSetCmd.Flags().String("key-value", "", "API key value")
+	authSetCmd.Flags().String("key-in", "header", "Where to send the API key: header or query")
+	authSetCmd.Flags().String("login-route", "", "Route that issues JWTs (jwt auth)")
+	authSetCmd.Flags().String("token-path", "$.token", "JSON path of the token in the login response (jwt auth)")
+
+	// Show command flags
+	authShowCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := authShowCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+
+	// Clear command flags
+	authClearCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := authClearCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/curl"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var routeCurlCmd = &cobra.Command{
+	Use:   "curl",
+	Short: "Print a route as a runnable curl command",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		routeName, _ := cmd.Flags().GetString("route")
+		paramPairs, _ := cmd.Flags().GetStringArray("param")
+		envName, _ := cmd.Flags().GetString("env")
+		varPairs, _ := cmd.Flags().GetStringArray("var")
+		noAuth, _ := cmd.Flags().GetBool("no-auth")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		r, err := storage.GetRouteByName(p.ID, routeName)
+		if err != nil {
+			return fmt.Errorf("route '%s' not found in project '%s': %w", routeName, p.Name, err)
+		}
+		params, err := parseKeyValues(paramPairs)
+		if err != nil {
+			return err
+		}
+		vars, err := parseKeyValues(varPairs)
+		if err != nil {
+			return err
+		}
+		run, err := newRunner(p, 0)
+		if err != nil {
+			return err
+		}
+		e, err := loadEnvironment(p, envName)
+		if err != nil {
+			return err
+		}
+		if e != nil {
+			run.UseEnvironment(e)
+		}
+		run.Params = params
+		maps.Copy(run.Vars, vars)
+		if noAuth {
+			r.Public = true
+		}
+		req, err := run.HTTPRequest(r)
+		if err != nil {
+			return fmt.Errorf("failed to build request for route '%s': %w", r.Name, err)
+		}
+		line, err := curl.Render(req)
+		if err != nil {
+			return err
+		}
+		fmt.Println(line)
+		return nil
+	},
+}
+
+// parseCurlRoute converts a curl command into route fields, splitting its URL
+// against the base URL of p or one of its environments
+func parseCurlRoute(p *project.Project, line string) (*route.Route, error) {
+	c, err := curl.Parse(line)
+	if err != nil {
+		return nil, fmt.Errorf("invalid curl command: %w", err)
+	}
+	method, err := route.ParseHTTPMethod(c.Method)
+	if err != nil {
+		return nil, err
+	}
+	bases := []string{p.BaseURL}
+	envs, err := storage.ListEnvironmentsByProject(p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	for _, e := range envs {
+		if e.BaseURL != "" {
+			bases = append(bases, e.BaseURL)
+		}
+	}
+	path, ok := "", false
+	for _, base := range bases {
+		if path, ok = strings.CutPrefix(c.URL, strings.TrimSuffix(base, "/")); ok && (path == "" || strings.HasPrefix(path, "/") || strings.HasPrefix(path, "?")) {
+			break
+		}
+		ok = false
+	}
+	if !ok {
+		return nil, fmt.Errorf("URL '%s' is not under the project base URL '%s'", c.URL, p.BaseURL)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	r := &route.Route{
+		Method:      method,
+		Path:        path,
+		Headers:     make(route.Headers),
+		ContentType: c.Headers.Get("Content-Type"),
+		Body:        c.Body,
+	}
+	for name, values := range c.Headers {
+		if name != "Content-Type" {
+			r.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	if c.Username != "" || c.Password != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(c.Username + ":" + c.Password))
+		r.Headers[http.CanonicalHeaderKey("Authorization")] = "Basic " + credentials
+	}
+	return r, nil
+}
+
+func init() {
+	routeCmd.AddCommand(routeCurlCmd)
+
+	routeCurlCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := routeCurlCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	routeCurlCmd.Flags().StringP("route", "r", "", "Route name (required)")
+	if err := routeCurlCmd.MarkFlagRequired("route"); err != nil {
+		panic(err)
+	}
+	routeCurlCmd.Flags().StringArray("param", nil, "Path parameter value as name=value (repeatable)")
+	routeCurlCmd.Flags().StringP("env", "e", "", "Environment to use (default = active environment)")
+	routeCurlCmd.Flags().StringArray("var", nil, "Variable value as name=value, overriding the environment (repeatable)")
+	routeCurlCmd.Flags().Bool("no-auth", false, "Leave out the project's credentials")
+}
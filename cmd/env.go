@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage project environments",
+}
+
+var envCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an environment for a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		name, _ := cmd.Flags().GetString("name")
+		baseURL, _ := cmd.Flags().GetString("url")
+		varPairs, _ := cmd.Flags().GetStringArray("var")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		if baseURL != "" {
+			if _, err := url.Parse(baseURL); err != nil {
+				return fmt.Errorf("invalid URL: %s", baseURL)
+			}
+		}
+		vars, err := parseKeyValues(varPairs)
+		if err != nil {
+			return err
+		}
+		e := &environment.Environment{
+			ProjectID: p.ID,
+			Name:      name,
+			BaseURL:   baseURL,
+			Variables: vars,
+		}
+		if err := storage.CreateEnvironment(e); err != nil {
+			return fmt.Errorf("failed to create environment: %w", err)
+		}
+		fmt.Printf("Environment '%s' created in project '%s'\n", e.Name, p.Name)
+		return nil
+	},
+}
+
+var envListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List environments for a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		envs, err := storage.ListEnvironmentsByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list environments: %w", err)
+		}
+		if len(envs) == 0 {
+			fmt.Printf("No environments found for project '%s'\n", p.Name)
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintln(w, "Active\tName\tBase URL\tVariables"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		for _, e := range envs {
+			active := ""
+			if e.Active {
+				active = "*"
+			}
+			baseURL := e.BaseURL
+			if baseURL == "" {
+				baseURL = p.BaseURL
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", active, e.Name, baseURL, formatVars(e.Variables)); err != nil {
+				return fmt.Errorf("failed to write table line: %w", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write environments table: %w", err)
+		}
+		return nil
+	},
+}
+
+var envSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set variables or the base URL of an environment",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		envName, _ := cmd.Flags().GetString("env")
+		varPairs, _ := cmd.Flags().GetStringArray("var")
+		unset, _ := cmd.Flags().GetStringArray("unset")
+		if !cmd.Flags().Changed("url") && len(varPairs) == 0 && len(unset) == 0 {
+			return fmt.Errorf("nothing to update: pass --url, --var or --unset")
+		}
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		e, err := storage.GetEnvironmentByName(p.ID, envName)
+		if err != nil {
+			return fmt.Errorf("environment '%s' not found in project '%s': %w", envName, p.Name, err)
+		}
+		updates := &environment.UpdateEnvironmentInput{}
+		if cmd.Flags().Changed("url") {
+			baseURL, _ := cmd.Flags().GetString("url")
+			if _, err := url.Parse(baseURL); err != nil {
+				return fmt.Errorf("invalid URL: %s", baseURL)
+			}
+			updates.BaseURL = &baseURL
+		}
+		if len(varPairs) > 0 || len(unset) > 0 {
+			vars, err := parseKeyValues(varPairs)
+			if err != nil {
+				return err
+			}
+			merged := make(map[string]string, len(e.Variables)+len(vars))
+			maps.Copy(merged, e.Variables)
+			for _, name := range unset {
+				delete(merged, name)
+			}
+			maps.Copy(merged, vars)
+			updates.Variables = &merged
+		}
+		if err := storage.UpdateEnvironment(e.ID, updates); err != nil {
+			return fmt.Errorf("failed to update environment '%s': %w", envName, err)
+		}
+		fmt.Printf("Environment '%s' updated\n", e.Name)
+		return nil
+	},
+}
+
+var envUseCmd = &cobra.Command{
+	Use:   "use",
+	Short: "Select the environment used by default when testing a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		envName, _ := cmd.Flags().GetString("env")
+		none, _ := cmd.Flags().GetBool("none")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		if none {
+			if err := storage.UseEnvironment(p.ID, 0); err != nil {
+				return fmt.Errorf("failed to clear environment: %w", err)
+			}
+			fmt.Printf("Project '%s' no longer uses an environment\n", p.Name)
+			return nil
+		}
+		if envName == "" {
+			return fmt.Errorf("either --env or --none is required")
+		}
+		e, err := storage.GetEnvironmentByName(p.ID, envName)
+		if err != nil {
+			return fmt.Errorf("environment '%s' not found in project '%s': %w", envName, p.Name, err)
+		}
+		if err := storage.UseEnvironment(p.ID, e.ID); err != nil {
+			return fmt.Errorf("failed to use environment: %w", err)
+		}
+		fmt.Printf("Project '%s' now uses environment '%s'\n", p.Name, e.Name)
+		return nil
+	},
+}
+
+var envDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete an environment",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		envName, _ := cmd.Flags().GetString("env")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		e, err := storage.GetEnvironmentByName(p.ID, envName)
+		if err != nil {
+			return fmt.Errorf("environment '%s' not found in project '%s': %w", envName, p.Name, err)
+		}
+		if err := storage.DeleteEnvironment(e.ID); err != nil {
+			return fmt.Errorf("failed to delete environment: %w", err)
+		}
+		fmt.Printf("Environment '%s' deleted from project '%s'\n", e.Name, p.Name)
+		return nil
+	},
+}
+
+// formatVars renders variables as sorted key=value pairs
+func formatVars(vars map[string]string) string {
+	pairs := make([]string, 0, len(vars))
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		pairs = append(pairs, name+"="+vars[name])
+	}
+	return strings.Join(pairs, " ")
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envCreateCmd)
+	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envSetCmd)
+	envCmd.AddCommand(envUseCmd)
+	envCmd.AddCommand(envDeleteCmd)
+
+	// Create command flags
+	envCreateCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := envCreateCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	envCreateCmd.Flags().StringP("name", "n", "", "Environment name (required)")
+	if err := envCreateCmd.MarkFlagRequired("name"); err != nil {
+		panic(err)
+	}
+	envCreateCmd.Flags().String("url", "", "Base URL override (optional)")
+	envCreateCmd.Flags().StringArray("var", nil, "Variable as name=value (repeatable)")
+
+	// List command flags
+	envListCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := envListCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+
+	// Set command flags
+	envSetCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := envSetCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	envSetCmd.Flags().StringP("env", "e", "", "Environment name (required)")
+	if err := envSetCmd.MarkFlagRequired("env"); err != nil {
+		panic(err)
+	}
+	envSetCmd.Flags().String("url", "", "Base URL override (empty string clears it)")
+	envSetCmd.Flags().StringArray("var", nil, "Set a variable as name=value (repeatable)")
+	envSetCmd.Flags().StringArray("unset", nil, "Remove a variable by name (repeatable)")
+
+	// Use command flags
+	envUseCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := envUseCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	envUseCmd.Flags().StringP("env", "e", "", "Environment name")
+	envUseCmd.Flags().Bool("none", false, "Stop using any environment")
+
+	// Delete command flags
+	envDeleteCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := envDeleteCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	envDeleteCmd.Flags().StringP("env", "e", "", "Environment name (required)")
+	if err := envDeleteCmd.MarkFlagRequired("env"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/raworiginal/goapi/internal/openapi"
+	"github.com/raworiginal/goapi/internal/postman"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export projects to other formats",
+}
+
+var exportPostmanCmd = &cobra.Command{
+	Use:   "postman",
+	Short: "Export a project as a Postman collection (v2.1)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		envName, _ := cmd.Flags().GetString("env")
+		outFile, _ := cmd.Flags().GetString("out-file")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		routes, err := storage.ListRoutesByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list routes for project: %w", err)
+		}
+		e, err := loadEnvironment(p, envName)
+		if err != nil {
+			return err
+		}
+		var vars map[string]string
+		if e != nil {
+			vars = e.Variables
+			if e.BaseURL != "" {
+				p.BaseURL = e.BaseURL
+			}
+		}
+		data, err := json.MarshalIndent(postman.Export(p, routes, vars), "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode collection: %w", err)
+		}
+		return writeOutput(data, outFile)
+	},
+}
+
+var exportOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Export a project as an OpenAPI 3.1 document",
+	Long: `Export a project as an OpenAPI 3.1 document.
+
+Each route becomes an operation. Request and response schemas are inferred from
+the stored request body and from the route's example response, or failing that
+its snapshot. The project's base URL and its environments' base URLs become the
+servers, and its auth profile the security scheme.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		format, _ := cmd.Flags().GetString("format")
+		outFile, _ := cmd.Flags().GetString("out-file")
+		if format != "yaml" && format != "json" {
+			return fmt.Errorf("invalid format: %s. Valid formats are: yaml, json", format)
+		}
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		routes, err := storage.ListRoutesByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list routes for project: %w", err)
+		}
+		envs, err := storage.ListEnvironmentsByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list environments: %w", err)
+		}
+		profile, err := storage.GetAuth(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load auth: %w", err)
+		}
+		snapshots, err := storage.ListSnapshotsByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load snapshots: %w", err)
+		}
+
+		opts := openapi.ExportOptions{Auth: profile, Recorded: make(map[uint]openapi.Recorded)}
+		for _, e := range envs {
+			if e.BaseURL != "" {
+				opts.Servers = append(opts.Servers, openapi.Server{URL: e.BaseURL, Description: e.Name})
+			}
+		}
+		for _, r := range routes {
+			if s := snapshots[r.ID]; s != nil {
+				status, _ := strconv.Atoi(r.Expect.Status)
+				opts.Recorded[r.ID] = openapi.Recorded{Status: status, Body: s.Body}
+			}
+		}
+		spec, skipped := openapi.Export(p, routes, opts)
+		for _, r := range skipped {
+			fmt.Fprintf(os.Stderr, "Skipped route '%s': %s %s is already described by an earlier route\n", r.Name, r.Method, r.Path)
+		}
+
+		var buf bytes.Buffer
+		if format == "json" {
+			enc := json.NewEncoder(&buf)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(spec)
+		} else {
+			enc := yaml.NewEncoder(&buf)
+			enc.SetIndent(2)
+			err = enc.Encode(spec)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode document: %w", err)
+		}
+		return writeOutput(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), outFile)
+	},
+}
+
+// writeOutput writes data to path, or to stdout when path is empty
+func writeOutput(data []byte, path string) error {
+	data = append(data, '\n')
+	if path == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportPostmanCmd)
+	exportCmd.AddCommand(exportOpenAPICmd)
+
+	// Postman command flags
+	exportPostmanCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := exportPostmanCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	exportPostmanCmd.Flags().StringP("env", "e", "", "Environment whose variables are exported (default = active)")
+	exportPostmanCmd.Flags().String("out-file", "", "Write the collection to a file instead of stdout")
+
+	// OpenAPI command flags
+	exportOpenAPICmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := exportOpenAPICmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	exportOpenAPICmd.Flags().String("format", "yaml", "Output format: yaml, json")
+	exportOpenAPICmd.Flags().String("out-file", "", "Write the document to a file instead of stdout")
+}
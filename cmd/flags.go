@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/extract"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/schema"
+	"github.com/raworiginal/goapi/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// parseKeyValues turns repeated key=value flag values into a map
+func parseKeyValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid key=value pair: %s", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// parseHeaders turns repeated "Name: value" flag values into route headers
+func parseHeaders(lines []string) (route.Headers, error) {
+	headers := make(route.Headers, len(lines))
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header '%s': expected \"Name: value\"", line)
+		}
+		headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// readBody returns the body flag value, loading it from a file when prefixed with '@'
+func readBody(value string) (string, error) {
+	file, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		return value, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body file '%s': %w", file, err)
+	}
+	return string(data), nil
+}
+
+// addExpectFlags registers the response expectation flags shared by route add and update
+func addExpectFlags(cmd *cobra.Command) {
+	cmd.Flags().String("expect-status", "", "Expected status: code (200), class (2xx) or range (200-299)")
+	cmd.Flags().StringArray("expect-header", nil, "Required response header as \"Name\" or \"Name: regex\" (repeatable)")
+	cmd.Flags().StringArray("expect-json", nil, "Body check: \"$.path exists\", \"$.path == value\" or \"$.path ~ regex\" (repeatable)")
+	cmd.Flags().Duration("max-duration", 0, "Maximum response time, e.g. 500ms")
+}
+
+// applyExpectFlags merges the expectation flags set on cmd into e and reports whether any were set
+func applyExpectFlags(cmd *cobra.Command, e *assert.Expectation) (bool, error) {
+	changed := false
+	if cmd.Flags().Changed("expect-status") {
+		status, _ := cmd.Flags().GetString("expect-status")
+		if status != "" {
+			if _, _, err := assert.ParseStatus(status); err != nil {
+				return false, err
+			}
+		}
+		e.Status = status
+		changed = true
+	}
+	headerLines, _ := cmd.Flags().GetStringArray("expect-header")
+	for _, line := range headerLines {
+		name, pattern, _ := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return false, fmt.Errorf("invalid expected header '%s'", line)
+		}
+		if e.Headers == nil {
+			e.Headers = make(map[string]string)
+		}
+		e.Headers[http.CanonicalHeaderKey(name)] = strings.TrimSpace(pattern)
+		changed = true
+	}
+	checks, _ := cmd.Flags().GetStringArray("expect-json")
+	for _, expr := range checks {
+		check, err := assert.ParseBodyCheck(expr)
+		if err != nil {
+			return false, err
+		}
+		e.Body = append(e.Body, check)
+		changed = true
+	}
+	if cmd.Flags().Changed("max-duration") {
+		e.MaxDuration, _ = cmd.Flags().GetDuration("max-duration")
+		changed = true
+	}
+	return changed, nil
+}
+
+// mergeExtractions applies --extract specs to existing extractions, replacing those with
+// the same name, and drops the names in remove
+func mergeExtractions(existing []extract.Extraction, specs, remove []string) ([]extract.Extraction, error) {
+	merged := slices.DeleteFunc(slices.Clone(existing), func(e extract.Extraction) bool {
+		return slices.Contains(remove, e.Name)
+	})
+	for _, spec := range specs {
+		e, err := extract.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		if i := slices.IndexFunc(merged, func(m extract.Extraction) bool { return m.Name == e.Name }); i >= 0 {
+			merged[i] = e
+		} else {
+			merged = append(merged, e)
+		}
+	}
+	return merged, nil
+}
+
+// mergeSnapshotIgnore adds the paths in add to existing ignored snapshot paths and drops those in remove
+func mergeSnapshotIgnore(existing, add, remove []string) ([]string, error) {
+	merged := slices.DeleteFunc(slices.Clone(existing), func(path string) bool {
+		return slices.Contains(remove, path)
+	})
+	for _, path := range add {
+		if err := snapshot.ValidateIgnore(path); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(merged, path) {
+			merged = append(merged, path)
+		}
+	}
+	return merged, nil
+}
+
+// resolveSchema checks that the --schema value loads and makes file references
+// absolute, so tests find the file from any directory
+func resolveSchema(ref string) (string, error) {
+	if ref == "" {
+		return "", nil
+	}
+	resolved, err := schema.Resolve(ref, ".")
+	if err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+	if _, err := schema.Load(resolved); err != nil {
+		return "", fmt.Errorf("invalid schema: %w", err)
+	}
+	return resolved, nil
+}
+
+func addExampleFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("example-status", 0, "Status code of the mock response (default = expected status or 200)")
+	cmd.Flags().StringArray("example-header", nil, "Mock response header as \"Name: value\" (repeatable)")
+	cmd.Flags().String("example-body", "", "Mock response body, inline or @file.json")
+}
+
+// applyExampleFlags merges the example response flags set on cmd into e and reports whether any were set
+func applyExampleFlags(cmd *cobra.Command, e *route.Example) (bool, error) {
+	changed := false
+	if cmd.Flags().Changed("example-status") {
+		e.Status, _ = cmd.Flags().GetInt("example-status")
+		if e.Status != 0 && (e.Status < 100 || e.Status > 599) {
+			return false, fmt.Errorf("invalid example status: %d", e.Status)
+		}
+		changed = true
+	}
+	headerLines, _ := cmd.Flags().GetStringArray("example-header")
+	if len(headerLines) > 0 {
+		headers, err := parseHeaders(headerLines)
+		if err != nil {
+			return false, err
+		}
+		if e.Headers == nil {
+			e.Headers = make(map[string]string)
+		}
+		maps.Copy(e.Headers, headers)
+		changed = true
+	}
+	if cmd.Flags().Changed("example-body") {
+		bodyFlag, _ := cmd.Flags().GetString("example-body")
+		body, err := readBody(bodyFlag)
+		if err != nil {
+			return false, err
+		}
+		e.Body = body
+		changed = true
+	}
+	return changed, nil
+}
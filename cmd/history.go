@@ -0,0 +1,241 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/history"
+	"github.com/raworiginal/goapi/internal/report"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Browse past test runs",
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent test runs for a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		limit, _ := cmd.Flags().GetInt("limit")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		runs, err := storage.ListRuns(p.ID, limit)
+		if err != nil {
+			return fmt.Errorf("failed to list runs: %w", err)
+		}
+		if len(runs) == 0 {
+			fmt.Printf("No runs recorded for project '%s'\n", p.Name)
+			return nil
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintln(w, "Run\tStarted\tSuite\tEnvironment\tRoutes\tPassed\tFailed\tDuration"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		for _, run := range runs {
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%v\n", run.ID, run.StartedAt.Local().Format("2006-01-02 15:04:05"), cmp.Or(run.Suite, "-"), run.Environment, run.Passed+run.Failed, run.Passed, run.Failed, run.Duration.Round(time.Millisecond)); err != nil {
+				return fmt.Errorf("failed to write table line: %w", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write runs table: %w", err)
+		}
+		return nil
+	},
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the results of a test run",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := cmd.Flags().GetUint("run")
+		showBody, _ := cmd.Flags().GetBool("body")
+		run, err := storage.GetRun(id)
+		if err != nil {
+			return fmt.Errorf("run %d not found: %w", id, err)
+		}
+		fmt.Printf("Run %d started %s", run.ID, run.StartedAt.Local().Format("2006-01-02 15:04:05"))
+		if run.Suite != "" {
+			fmt.Printf(" for suite '%s'", run.Suite)
+		}
+		if run.Environment != "" {
+			fmt.Printf(" in environment '%s'", run.Environment)
+		}
+		fmt.Printf(": %d passed, %d failed\n\n", run.Passed, run.Failed)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintln(w, "ID\tName\tMethod\tPath\tStatus\tDuration\tResult"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		for _, r := range run.Results {
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%v\t%s\n", r.RouteID, r.RouteName, r.Method, r.Path, statusLabel(r.StatusCode, r.Error), r.Duration, passLabel(r.Passed)); err != nil {
+				return fmt.Errorf("failed to write table line: %w", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write results table: %w", err)
+		}
+		for _, r := range run.Results {
+			for _, line := range r.Failures {
+				fmt.Printf("%s: %s\n", r.RouteName, line)
+			}
+			if showBody && r.ResponseBody != "" {
+				fmt.Printf("\n--- %s ---\n%s\n", r.RouteName, r.ResponseBody)
+			}
+		}
+		return nil
+	},
+}
+
+var historyDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two test runs route by route",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fromID, _ := cmd.Flags().GetUint("from")
+		toID, _ := cmd.Flags().GetUint("to")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		from, err := storage.GetRun(fromID)
+		if err != nil {
+			return fmt.Errorf("run %d not found: %w", fromID, err)
+		}
+		to, err := storage.GetRun(toID)
+		if err != nil {
+			return fmt.Errorf("run %d not found: %w", toID, err)
+		}
+		if from.Suite != to.Suite {
+			return fmt.Errorf("run %d and run %d are not comparable: %s vs %s", from.ID, to.ID, runKind(from), runKind(to))
+		}
+		changes := history.Diff(from, to, threshold)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if _, err := fmt.Fprintf(w, "Name\tStatus #%d\tStatus #%d\tDuration #%d\tDuration #%d\tDelta\tChange\n", from.ID, to.ID, from.ID, to.ID); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		regressions := 0
+		for _, c := range changes {
+			if c.Regression() {
+				regressions++
+			}
+			fromStatus, fromDuration := "-", "-"
+			if c.From != nil {
+				fromStatus, fromDuration = statusLabel(c.From.StatusCode, c.From.Error), c.From.Duration.String()
+			}
+			toStatus, toDuration := "-", "-"
+			if c.To != nil {
+				toStatus, toDuration = statusLabel(c.To.StatusCode, c.To.Error), c.To.Duration.String()
+			}
+			delta := ""
+			if c.From != nil && c.To != nil {
+				delta = fmt.Sprintf("%+v", c.DurationDelta())
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", c.RouteName, fromStatus, toStatus, fromDuration, toDuration, delta, strings.Join(c.Notes, ", ")); err != nil {
+				return fmt.Errorf("failed to write table line: %w", err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write diff table: %w", err)
+		}
+		fmt.Printf("\n%d regressions\n", regressions)
+		return nil
+	},
+}
+
+// recordRun stores a finished report in the history tables. suiteName is empty for
+// goapi test runs; suite results are numbered by step.
+func recordRun(projectID uint, suiteName string, rep *report.Report) (*history.Run, error) {
+	run := &history.Run{
+		ProjectID:   projectID,
+		Environment: rep.Environment,
+		Suite:       suiteName,
+		StartedAt:   rep.StartedAt,
+		Duration:    rep.Duration,
+		Passed:      rep.Passed,
+		Failed:      rep.Failed,
+	}
+	for i, r := range rep.Results {
+		step := 0
+		if suiteName != "" {
+			step = i + 1
+		}
+		run.Results = append(run.Results, history.Result{
+			RouteID:      r.RouteID,
+			Step:         step,
+			RouteName:    r.RouteName,
+			Method:       r.Method,
+			Path:         r.Path,
+			StatusCode:   r.StatusCode,
+			Duration:     r.Duration,
+			Passed:       r.Passed(),
+			Error:        r.Error,
+			Failures:     r.Failures(),
+			ResponseBody: history.Truncate(r.ResponseBody),
+		})
+	}
+	if err := storage.CreateRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// runKind describes what produced a run
+func runKind(run *history.Run) string {
+	if run.Suite == "" {
+		return "goapi test"
+	}
+	return fmt.Sprintf("suite '%s'", run.Suite)
+}
+
+func statusLabel(code int, errMsg string) string {
+	if errMsg != "" && code == 0 {
+		return "Error"
+	}
+	return fmt.Sprintf("%d", code)
+}
+
+func passLabel(passed bool) string {
+	if passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	historyCmd.AddCommand(historyDiffCmd)
+
+	// List command flags
+	historyListCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := historyListCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	historyListCmd.Flags().Int("limit", 20, "Number of runs to show (0 for all)")
+
+	// Show command flags
+	historyShowCmd.Flags().Uint("run", 0, "Run ID (required)")
+	if err := historyShowCmd.MarkFlagRequired("run"); err != nil {
+		panic(err)
+	}
+	historyShowCmd.Flags().Bool("body", false, "Print the recorded response bodies")
+
+	// Diff command flags
+	historyDiffCmd.Flags().Uint("from", 0, "Earlier run ID (required)")
+	if err := historyDiffCmd.MarkFlagRequired("from"); err != nil {
+		panic(err)
+	}
+	historyDiffCmd.Flags().Uint("to", 0, "Later run ID (required)")
+	if err := historyDiffCmd.MarkFlagRequired("to"); err != nil {
+		panic(err)
+	}
+	historyDiffCmd.Flags().Float64("threshold", 20, "Latency change in percent reported as slower or faster")
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/har"
+	"github.com/raworiginal/goapi/internal/openapi"
+	"github.com/raworiginal/goapi/internal/postman"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import projects and routes from other formats",
+}
+
+var importOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Import an OpenAPI 3 or Swagger 2 document",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		projectName, _ := cmd.Flags().GetString("project")
+		baseURL, _ := cmd.Flags().GetString("url")
+		merge, _ := cmd.Flags().GetBool("merge")
+		doc, err := openapi.Load(file)
+		if err != nil {
+			return fmt.Errorf("failed to read spec '%s': %w", file, err)
+		}
+		routes, err := doc.Routes()
+		if err != nil {
+			return fmt.Errorf("failed to convert spec: %w", err)
+		}
+		p := doc.Project(projectName)
+		if baseURL != "" {
+			p.BaseURL = baseURL
+		}
+		_, err = importRoutes(p, routes, merge)
+		return err
+	},
+}
+
+var importPostmanCmd = &cobra.Command{
+	Use:   "postman",
+	Short: "Import a Postman collection (v2.1)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		projectName, _ := cmd.Flags().GetString("project")
+		baseURL, _ := cmd.Flags().GetString("url")
+		merge, _ := cmd.Flags().GetBool("merge")
+		envName, _ := cmd.Flags().GetString("env")
+		c, err := postman.Load(file)
+		if err != nil {
+			return fmt.Errorf("failed to read collection '%s': %w", file, err)
+		}
+		imported, err := c.Convert(baseURL)
+		if err != nil {
+			return fmt.Errorf("failed to convert collection: %w", err)
+		}
+		for _, reason := range imported.Skipped {
+			fmt.Fprintf(os.Stderr, "Skipped %s\n", reason)
+		}
+		if projectName != "" {
+			imported.Project.Name = projectName
+		}
+		p, err := importRoutes(imported.Project, imported.Routes, merge)
+		if err != nil {
+			return err
+		}
+		if len(imported.Variables) == 0 {
+			return nil
+		}
+		envs, err := storage.ListEnvironmentsByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list environments: %w", err)
+		}
+		if i := slices.IndexFunc(envs, func(e *environment.Environment) bool { return e.Name == envName }); i >= 0 {
+			e := envs[i]
+			merged := maps.Clone(e.Variables)
+			if merged == nil {
+				merged = make(map[string]string)
+			}
+			maps.Copy(merged, imported.Variables)
+			if err := storage.UpdateEnvironment(e.ID, &environment.UpdateEnvironmentInput{Variables: &merged}); err != nil {
+				return fmt.Errorf("failed to update environment '%s': %w", envName, err)
+			}
+			fmt.Printf("Updated %d collection variables in environment '%s'\n", len(imported.Variables), envName)
+			return nil
+		}
+		e := &environment.Environment{ProjectID: p.ID, Name: envName, Variables: imported.Variables}
+		if err := storage.CreateEnvironment(e); err != nil {
+			return fmt.Errorf("failed to create environment '%s': %w", envName, err)
+		}
+		if active, err := storage.GetActiveEnvironment(p.ID); err == nil && active == nil {
+			if err := storage.UseEnvironment(p.ID, e.ID); err != nil {
+				return fmt.Errorf("failed to activate environment '%s': %w", envName, err)
+			}
+		}
+		fmt.Printf("Stored %d collection variables in environment '%s'\n", len(imported.Variables), envName)
+		return nil
+	},
+}
+
+var importHARCmd = &cobra.Command{
+	Use:   "har",
+	Short: "Import routes from a HAR capture",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		projectName, _ := cmd.Flags().GetString("project")
+		host, _ := cmd.Flags().GetString("filter-host")
+		baseURL, _ := cmd.Flags().GetString("url")
+		merge, _ := cmd.Flags().GetBool("merge")
+		expect, _ := cmd.Flags().GetBool("expect")
+		archive, err := har.Load(file)
+		if err != nil {
+			return fmt.Errorf("failed to read HAR file '%s': %w", file, err)
+		}
+		imported, err := archive.Convert(har.Options{Host: host, BaseURL: baseURL, Expect: expect})
+		if err != nil {
+			return fmt.Errorf("failed to convert HAR file: %w", err)
+		}
+		if imported.OtherHosts > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d requests to other hosts (use --filter-host to choose one)\n", imported.OtherHosts)
+		}
+		if imported.Duplicates > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d duplicate requests\n", imported.Duplicates)
+		}
+		p := &project.Project{Name: projectName, BaseURL: imported.BaseURL}
+		_, err = importRoutes(p, imported.Routes, merge)
+		return err
+	},
+}
+
+// importRoutes stores routes under p, creating the project if it does not exist.
+// Existing projects are only extended when merge is set, skipping routes whose name is taken.
+// It returns the stored project.
+func importRoutes(p *project.Project, routes []*route.Route, merge bool) (*project.Project, error) {
+	if p.Name == "" {
+		return nil, fmt.Errorf("project name is required (use --project)")
+	}
+	existing, err := storage.FindProject(p.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project '%s': %w", p.Name, err)
+	}
+	if existing != nil {
+		if !merge {
+			return nil, fmt.Errorf("project '%s' already exists (use --merge to add routes to it)", p.Name)
+		}
+		p = existing
+	} else {
+		if p.BaseURL == "" {
+			return nil, fmt.Errorf("no base URL found for project '%s' (use --url)", p.Name)
+		}
+		if _, err := url.Parse(p.BaseURL); err != nil {
+			return nil, fmt.Errorf("invalid URL: %s", p.BaseURL)
+		}
+	}
+
+	taken := make(map[string]bool)
+	if p.ID != 0 {
+		current, err := storage.ListRoutesByProject(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list routes for project: %w", err)
+		}
+		for _, r := range current {
+			taken[r.Name] = true
+		}
+	}
+	var added []*route.Route
+	skipped := 0
+	for _, r := range routes {
+		if taken[r.Name] {
+			skipped++
+			continue
+		}
+		added = append(added, r)
+	}
+	if err := storage.ImportRoutes(p, added); err != nil {
+		return nil, fmt.Errorf("failed to import routes: %w", err)
+	}
+	fmt.Printf("Imported %d routes into project '%s'", len(added), p.Name)
+	if skipped > 0 {
+		fmt.Printf(" (%d skipped, name already in use)", skipped)
+	}
+	fmt.Println()
+	return p, nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importOpenAPICmd)
+	importCmd.AddCommand(importPostmanCmd)
+	importCmd.AddCommand(importHARCmd)
+
+	// OpenAPI command flags
+	importOpenAPICmd.Flags().StringP("file", "f", "", "OpenAPI/Swagger file, YAML or JSON (required)")
+	if err := importOpenAPICmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+	importOpenAPICmd.Flags().StringP("project", "p", "", "Project name (default = spec title)")
+	importOpenAPICmd.Flags().String("url", "", "Base URL (default = first server in the spec)")
+	importOpenAPICmd.Flags().Bool("merge", false, "Add routes to an existing project")
+
+	// Postman command flags
+	importPostmanCmd.Flags().StringP("file", "f", "", "Postman collection file, v2.1 JSON (required)")
+	if err := importPostmanCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+	importPostmanCmd.Flags().StringP("project", "p", "", "Project name (default = collection name)")
+	importPostmanCmd.Flags().String("url", "", "Base URL (default = baseUrl collection variable)")
+	importPostmanCmd.Flags().Bool("merge", false, "Add routes to an existing project")
+	importPostmanCmd.Flags().StringP("env", "e", "postman", "Environment that receives the collection variables")
+
+	// HAR command flags
+	importHARCmd.Flags().StringP("file", "f", "", "HAR file (required)")
+	if err := importHARCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+	importHARCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := importHARCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	importHARCmd.Flags().String("filter-host", "", "Only import requests to this host (default = host of the first request)")
+	importHARCmd.Flags().String("url", "", "Base URL stripped from request URLs (default = scheme and host)")
+	importHARCmd.Flags().Bool("merge", false, "Add routes to an existing project")
+	importHARCmd.Flags().Bool("expect", false, "Record captured responses as expectations")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/load"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Load test a route and report latency percentiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		routeName, _ := cmd.Flags().GetString("route")
+		rps, _ := cmd.Flags().GetFloat64("rps")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		paramPairs, _ := cmd.Flags().GetStringArray("param")
+		envName, _ := cmd.Flags().GetString("env")
+		varPairs, _ := cmd.Flags().GetStringArray("var")
+		output, _ := cmd.Flags().GetString("output")
+		outFile, _ := cmd.Flags().GetString("out-file")
+		if output != "table" && output != "json" {
+			return fmt.Errorf("invalid output format: %s. Valid formats are: table, json", output)
+		}
+		if rps < 0 || duration <= 0 || concurrency < 1 {
+			return fmt.Errorf("--rps must not be negative, --duration must be positive and --concurrency at least 1")
+		}
+		params, err := parseKeyValues(paramPairs)
+		if err != nil {
+			return err
+		}
+		vars, err := parseKeyValues(varPairs)
+		if err != nil {
+			return err
+		}
+
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		r, err := storage.GetRouteByName(p.ID, routeName)
+		if err != nil {
+			return fmt.Errorf("route '%s' not found in project '%s': %w", routeName, p.Name, err)
+		}
+		run, err := newRunner(p, timeout)
+		if err != nil {
+			return err
+		}
+		e, err := loadEnvironment(p, envName)
+		if err != nil {
+			return err
+		}
+		if e != nil {
+			run.UseEnvironment(e)
+		}
+		run.Params = params
+		maps.Copy(run.Vars, vars)
+		req, err := run.Request(r)
+		if err != nil {
+			return fmt.Errorf("failed to build request for route '%s': %w", r.Name, err)
+		}
+
+		// the route's expected status decides what counts as an error, otherwise any 4xx or 5xx
+		lo, hi := 100, 399
+		if r.Expect.Status != "" {
+			if lo, hi, err = assert.ParseStatus(r.Expect.Status); err != nil {
+				return err
+			}
+		}
+		ok := func(status int) bool { return status >= lo && status <= hi }
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		opts := load.Options{RPS: rps, Duration: duration, Concurrency: concurrency}
+		fmt.Fprintf(os.Stderr, "Sending %s %s for %s...\n", req.Method, req.URL, duration)
+		startedAt := time.Now()
+		summary := load.Run(ctx, func(ctx context.Context) (*api.Response, error) {
+			return run.SendRequest(ctx, req)
+		}, ok, opts)
+		rep := &load.Report{
+			Project:   p.Name,
+			Route:     r.Name,
+			Method:    req.Method,
+			URL:       req.URL,
+			StartedAt: startedAt,
+			Target:    opts,
+			Summary:   summary,
+		}
+
+		out := os.Stdout
+		if outFile != "" {
+			if out, err = os.Create(outFile); err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+		}
+		if output == "json" {
+			err = rep.WriteJSON(out)
+		} else {
+			err = summary.WriteTable(out)
+		}
+		if outFile != "" {
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write results: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loadCmd)
+
+	loadCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := loadCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	loadCmd.Flags().StringP("route", "r", "", "Route name (required)")
+	if err := loadCmd.MarkFlagRequired("route"); err != nil {
+		panic(err)
+	}
+	loadCmd.Flags().Float64("rps", 10, "Target requests per second (0 for as fast as possible)")
+	loadCmd.Flags().Duration("duration", 10*time.Second, "How long to send requests")
+	loadCmd.Flags().IntP("concurrency", "c", 5, "Maximum requests in flight")
+	loadCmd.Flags().Duration("timeout", 5*time.Second, "Request timeout")
+	loadCmd.Flags().StringArray("param", nil, "Path parameter value as name=value (repeatable)")
+	loadCmd.Flags().StringP("env", "e", "", "Environment name (defaults to the project's active environment)")
+	loadCmd.Flags().StringArray("var", nil, "Variable as name=value, overriding the environment (repeatable)")
+	loadCmd.Flags().StringP("output", "o", "table", "Output format: table, json")
+	loadCmd.Flags().String("out-file", "", "Write results to a file instead of stdout")
+}
@@ -13,13 +13,22 @@ var rootCmd = &cobra.Command{
 	Short: "A Go-based API testing CLI/TUI",
 	Long:  `goapi is a command-line and TUI tool for testing and managing API routes.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		return storage.InitDB()
+		dbFlag, _ := cmd.Flags().GetString("db")
+		dbPath, err := storage.ResolvePath(dbFlag)
+		if err != nil {
+			return fmt.Errorf("failed to locate database: %w", err)
+		}
+		return storage.InitDB(dbPath)
 	},
 }
 
+func init() {
+	rootCmd.PersistentFlags().String("db", "", "SQLite database file (default = $"+storage.EnvVar+", else "+storage.WorkspaceDir+"/goapi.db in this or a parent directory, else ~/.config/goapi/goapi.db)")
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/manifest"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create or update a project from a goapi.yaml manifest",
+	Long: `Create or update a project from a goapi.yaml manifest.
+
+The plan of creates, updates and deletes is printed first and applied after
+confirmation. Environments and routes are matched by name; those stored but not
+in the manifest are deleted, and routes run in the order they are listed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		m, err := manifest.Load(file)
+		if err != nil {
+			return err
+		}
+		p, err := storage.FindProject(m.Project.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", m.Project.Name, err)
+		}
+		var current *manifest.Manifest
+		var usedBy map[string][]string
+		if p != nil {
+			if current, err = loadManifest(p.Name); err != nil {
+				return err
+			}
+			if usedBy, err = routeReferences(p.ID); err != nil {
+				return err
+			}
+		}
+		changes, err := manifest.Plan(current, m, usedBy)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			fmt.Printf("Project '%s' is up to date\n", m.Project.Name)
+			return nil
+		}
+		for _, c := range changes {
+			fmt.Println(c)
+		}
+		if blocked := manifest.Blocked(changes); len(blocked) > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("refusing to delete %d route(s) still in use; keep them in the manifest or remove the references first", len(blocked))
+		}
+		if dryRun {
+			return nil
+		}
+		if !yes {
+			if !stdinIsTerminal() {
+				return fmt.Errorf("stdin is not a terminal, pass --yes to apply without confirmation")
+			}
+			if !confirm("Apply these changes?") {
+				return fmt.Errorf("aborted, no changes applied")
+			}
+		}
+
+		desired, envs, routes, err := m.Models()
+		if err != nil {
+			return err
+		}
+		if p != nil {
+			desired.ID = p.ID
+		}
+		if err := storage.SyncProject(desired, envs, routes); err != nil {
+			return fmt.Errorf("failed to apply manifest: %w", err)
+		}
+		fmt.Printf("Applied %d change(s) to project '%s'\n", len(changes), desired.Name)
+		return nil
+	},
+}
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Write a project as a goapi.yaml manifest",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		outFile, _ := cmd.Flags().GetString("out-file")
+		m, err := loadManifest(projectName)
+		if err != nil {
+			return err
+		}
+		dir, err := filepath.Abs(filepath.Dir(cmp.Or(outFile, ".")))
+		if err != nil {
+			return err
+		}
+		m.RelativeTo(dir)
+		var buf bytes.Buffer
+		if err := m.Write(&buf); err != nil {
+			return fmt.Errorf("failed to encode manifest: %w", err)
+		}
+		return writeOutput(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), outFile)
+	},
+}
+
+// loadManifest describes a stored project as a manifest
+func loadManifest(projectName string) (*manifest.Manifest, error) {
+	p, err := storage.GetProject(projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project '%s': %w", projectName, err)
+	}
+	envs, err := storage.ListEnvironmentsByProject(p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	routes, err := storage.ListRoutesByProject(p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes for project: %w", err)
+	}
+	return manifest.FromModels(p, envs, routes), nil
+}
+
+// routeReferences lists, by route name, what outside the manifest uses each route of
+// a project: the JWT login of its auth profile and the steps of its suites
+func routeReferences(projectID uint) (map[string][]string, error) {
+	routes, err := routesByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	usedBy := make(map[string][]string)
+	profile, err := storage.GetAuth(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auth: %w", err)
+	}
+	if profile != nil && profile.Type == auth.JWT {
+		if r, ok := routes[profile.LoginRouteID]; ok {
+			usedBy[r.Name] = append(usedBy[r.Name], "auth login")
+		}
+	}
+	suites, err := storage.ListSuitesByProject(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suites: %w", err)
+	}
+	for _, s := range suites {
+		label := fmt.Sprintf("suite '%s'", s.Name)
+		for _, step := range s.Steps {
+			if r, ok := routes[step.RouteID]; ok && !slices.Contains(usedBy[r.Name], label) {
+				usedBy[r.Name] = append(usedBy[r.Name], label)
+			}
+		}
+	}
+	return usedBy, nil
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// stdinIsTerminal reports whether stdin is interactive, so a prompt can be answered
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	rootCmd.AddCommand(dumpCmd)
+
+	// Apply command flags
+	applyCmd.Flags().StringP("file", "f", manifest.DefaultFile, "Manifest to apply")
+	applyCmd.Flags().Bool("dry-run", false, "Print the plan without applying it")
+	applyCmd.Flags().BoolP("yes", "y", false, "Apply without asking for confirmation")
+
+	// Dump command flags
+	dumpCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := dumpCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	dumpCmd.Flags().String("out-file", "", "Write the manifest to a file instead of stdout")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/mock"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var mockCmd = &cobra.Command{
+	Use:   "mock",
+	Short: "Serve a project's routes from their example responses",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		host, _ := cmd.Flags().GetString("host")
+		port, _ := cmd.Flags().GetInt("port")
+		cors, _ := cmd.Flags().GetBool("cors")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		routes, err := storage.ListRoutesByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list routes for project: %w", err)
+		}
+		if len(routes) == 0 {
+			return fmt.Errorf("project '%s' has no routes to serve", p.Name)
+		}
+
+		handler := mock.New(p.BaseURL, routes, log.New(os.Stdout, "", log.Ltime))
+		handler.CORS = cors
+		server := &http.Server{
+			Addr:              net.JoinHostPort(host, strconv.Itoa(port)),
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+		}
+		fmt.Printf("Mocking %d routes of project '%s' on http://%s (Ctrl-C to stop)\n", len(routes), p.Name, listener.Addr())
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mockCmd)
+
+	mockCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := mockCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	mockCmd.Flags().Int("port", 8080, "Port to listen on")
+	mockCmd.Flags().String("host", "127.0.0.1", "Address to listen on")
+	mockCmd.Flags().Bool("cors", true, "Allow cross-origin requests from browsers")
+}
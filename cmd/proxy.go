@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/proxy"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Forward traffic to an API and record each request as a route",
+	Long: `Forward traffic to an API and record each request as a route.
+
+Every method and path seen creates a route in the project, or updates the
+matching route, with the last request and its response as the route's example.
+The project is created with the target as its base URL if it does not exist.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		targetURL, _ := cmd.Flags().GetString("target")
+		listen, _ := cmd.Flags().GetString("listen")
+		keepAuth, _ := cmd.Flags().GetBool("keep-auth")
+		target, err := url.Parse(targetURL)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return fmt.Errorf("invalid target URL: %s", targetURL)
+		}
+
+		p, err := storage.FindProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		if p == nil {
+			p = &project.Project{Name: projectName, BaseURL: targetURL}
+			if err := storage.CreateProject(p); err != nil {
+				return fmt.Errorf("failed to create project: %w", err)
+			}
+			fmt.Printf("Created project '%s'\n", p.Name)
+		}
+		routes, err := storage.ListRoutesByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list routes for project: %w", err)
+		}
+
+		logger := log.New(os.Stdout, "", log.Ltime)
+		recorder := proxy.NewRecorder(p.ID, routes)
+		recorder.KeepAuth = keepAuth
+		recorder.Create = storage.CreateRoute
+		recorder.Update = storage.UpdateRoute
+		handler := proxy.New(target, func(ex *proxy.Exchange) {
+			r, created, err := recorder.Record(ex)
+			switch {
+			case err != nil:
+				logger.Printf("%s %s -> %d (not recorded: %v)", ex.Method, ex.Path, ex.Status, err)
+			case created:
+				logger.Printf("%s %s -> %d %v (created route '%s')", ex.Method, ex.Path, ex.Status, ex.Duration.Round(time.Millisecond), r.Name)
+			default:
+				logger.Printf("%s %s -> %d %v (updated route '%s')", ex.Method, ex.Path, ex.Status, ex.Duration.Round(time.Millisecond), r.Name)
+			}
+		})
+		handler.ErrorLog = logger
+
+		server := &http.Server{
+			Addr:              listen,
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		listener, err := net.Listen("tcp", server.Addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", server.Addr, err)
+		}
+		fmt.Printf("Proxying http://%s to %s for project '%s' (Ctrl-C to stop)\n", listener.Addr(), target, p.Name)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(proxyCmd)
+
+	proxyCmd.Flags().StringP("project", "p", "", "Project to record routes into (required)")
+	if err := proxyCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	proxyCmd.Flags().String("target", "", "URL of the API to forward to (required)")
+	if err := proxyCmd.MarkFlagRequired("target"); err != nil {
+		panic(err)
+	}
+	proxyCmd.Flags().String("listen", ":9000", "Address to listen on")
+	proxyCmd.Flags().Bool("keep-auth", false, "Record Authorization and Cookie headers")
+}
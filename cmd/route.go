@@ -2,10 +2,13 @@ package main
 
 import (
 	"fmt"
+	"maps"
+	"net/http"
 	"os"
 	"strings"
 	"text/tabwriter"
 
+	"github.com/raworiginal/goapi/internal/assert"
 	"github.com/raworiginal/goapi/internal/route"
 	"github.com/raworiginal/goapi/internal/storage"
 	"github.com/spf13/cobra"
@@ -24,25 +27,109 @@ var routeAddCmd = &cobra.Command{
 		methodStr, _ := cmd.Flags().GetString("method")
 		path, _ := cmd.Flags().GetString("path")
 		description, _ := cmd.Flags().GetString("description")
+		paramPairs, _ := cmd.Flags().GetStringArray("param")
+		headerLines, _ := cmd.Flags().GetStringArray("header")
+		contentType, _ := cmd.Flags().GetString("content-type")
+		bodyFlag, _ := cmd.Flags().GetString("body")
+		public, _ := cmd.Flags().GetBool("public")
+		fromCurl, _ := cmd.Flags().GetString("from-curl")
 		p, err := storage.GetProject(projectName)
 		name, _ := cmd.Flags().GetString("name")
 		if err != nil {
 			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
 		}
+		headers, err := parseHeaders(headerLines)
+		if err != nil {
+			return err
+		}
+		body, err := readBody(bodyFlag)
+		if err != nil {
+			return err
+		}
+		// flags given alongside --from-curl override what the command specifies
+		if fromCurl != "" {
+			parsed, err := parseCurlRoute(p, fromCurl)
+			if err != nil {
+				return err
+			}
+			if methodStr == "" {
+				methodStr = string(parsed.Method)
+			}
+			if path == "" {
+				path = parsed.Path
+			}
+			maps.Copy(parsed.Headers, headers)
+			headers = parsed.Headers
+			if contentType == "" {
+				contentType = parsed.ContentType
+			}
+			if body == "" {
+				body = parsed.Body
+			}
+		}
+		if methodStr == "" || path == "" {
+			return fmt.Errorf("--method and --path are required unless --from-curl is given")
+		}
 		methodStr = strings.ToUpper(methodStr)
 		httpMethod, err := route.ParseHTTPMethod(methodStr)
 		if err != nil {
 			return fmt.Errorf("invalid HTTP method '%s': %w", methodStr, err)
 		}
+		defaults, err := parseKeyValues(paramPairs)
+		if err != nil {
+			return err
+		}
+		params, err := route.BuildParams(path, defaults)
+		if err != nil {
+			return fmt.Errorf("invalid path '%s': %w", path, err)
+		}
+		if body != "" && contentType == "" {
+			contentType = "application/json"
+		}
+		var expect assert.Expectation
+		if _, err := applyExpectFlags(cmd, &expect); err != nil {
+			return err
+		}
+		extractSpecs, _ := cmd.Flags().GetStringArray("extract")
+		extractions, err := mergeExtractions(nil, extractSpecs, nil)
+		if err != nil {
+			return err
+		}
+		order, _ := cmd.Flags().GetInt("order")
+		ignorePaths, _ := cmd.Flags().GetStringArray("snapshot-ignore")
+		snapshotIgnore, err := mergeSnapshotIgnore(nil, ignorePaths, nil)
+		if err != nil {
+			return err
+		}
+		responseSchema, _ := cmd.Flags().GetString("schema")
+		responseSchema, err = resolveSchema(responseSchema)
+		if err != nil {
+			return err
+		}
+		var example route.Example
+		if _, err := applyExampleFlags(cmd, &example); err != nil {
+			return err
+		}
 		if name == "" {
 			name = string(httpMethod) + " " + path[1:]
 		}
 		r := &route.Route{
-			ProjectID:   p.ID,
-			Name:        name,
-			Method:      httpMethod,
-			Path:        path,
-			Description: description,
+			ProjectID:      p.ID,
+			Name:           name,
+			Method:         httpMethod,
+			Path:           path,
+			Params:         params,
+			Headers:        headers,
+			ContentType:    contentType,
+			Body:           body,
+			Public:         public,
+			Expect:         expect,
+			Extract:        extractions,
+			Order:          order,
+			Example:        example,
+			ResponseSchema: responseSchema,
+			Description:    description,
+			SnapshotIgnore: snapshotIgnore,
 		}
 		if err := storage.CreateRoute(r); err != nil {
 			return fmt.Errorf("failed to create route: %w", err)
@@ -70,11 +157,11 @@ var routeListCmd = &cobra.Command{
 			return nil
 		}
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		if _, err := fmt.Fprintln(w, "ID\tName\tMethod\tPath"); err != nil {
+		if _, err := fmt.Fprintln(w, "Order\tID\tName\tMethod\tPath"); err != nil {
 			return fmt.Errorf("failed to write header: %w", err)
 		}
 		for _, r := range routes {
-			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.ID, r.Name, r.Method, r.Path); err != nil {
+			if _, err := fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\n", r.Order, r.ID, r.Name, r.Method, r.Path); err != nil {
 				return fmt.Errorf("failed to write table line: %w", err)
 			}
 		}
@@ -95,6 +182,9 @@ var routeUpdateCmd = &cobra.Command{
 		path, _ := cmd.Flags().GetString("path")
 		description, _ := cmd.Flags().GetString("description")
 		newName, _ := cmd.Flags().GetString("rename")
+		paramPairs, _ := cmd.Flags().GetStringArray("param")
+		headerLines, _ := cmd.Flags().GetStringArray("header")
+		removeHeaders, _ := cmd.Flags().GetStringArray("remove-header")
 		p, err := storage.GetProject(projectName)
 		if err != nil {
 			return fmt.Errorf("project not found: %w", err)
@@ -115,6 +205,102 @@ var routeUpdateCmd = &cobra.Command{
 		if path != "" {
 			updates.Path = &path
 		}
+		if path != "" || len(paramPairs) > 0 {
+			newPath := path
+			if newPath == "" {
+				newPath = r.Path
+			}
+			overrides, err := parseKeyValues(paramPairs)
+			if err != nil {
+				return err
+			}
+			params, err := route.RebuildParams(r.Params, newPath, overrides)
+			if err != nil {
+				return fmt.Errorf("invalid path '%s': %w", newPath, err)
+			}
+			updates.Params = &params
+		}
+		if len(headerLines) > 0 || len(removeHeaders) > 0 {
+			headers, err := parseHeaders(headerLines)
+			if err != nil {
+				return err
+			}
+			merged := make(route.Headers, len(r.Headers)+len(headers))
+			maps.Copy(merged, r.Headers)
+			for _, name := range removeHeaders {
+				delete(merged, http.CanonicalHeaderKey(name))
+			}
+			maps.Copy(merged, headers)
+			updates.Headers = &merged
+		}
+		if cmd.Flags().Changed("content-type") {
+			contentType, _ := cmd.Flags().GetString("content-type")
+			updates.ContentType = &contentType
+		}
+		if cmd.Flags().Changed("body") {
+			bodyFlag, _ := cmd.Flags().GetString("body")
+			body, err := readBody(bodyFlag)
+			if err != nil {
+				return err
+			}
+			updates.Body = &body
+		}
+		if cmd.Flags().Changed("public") {
+			public, _ := cmd.Flags().GetBool("public")
+			updates.Public = &public
+		}
+		expect := r.Expect
+		if clearExpect, _ := cmd.Flags().GetBool("clear-expect"); clearExpect {
+			expect = assert.Expectation{}
+		}
+		expectChanged, err := applyExpectFlags(cmd, &expect)
+		if err != nil {
+			return err
+		}
+		if expectChanged || cmd.Flags().Changed("clear-expect") {
+			updates.Expect = &expect
+		}
+		extractSpecs, _ := cmd.Flags().GetStringArray("extract")
+		removeExtract, _ := cmd.Flags().GetStringArray("remove-extract")
+		if len(extractSpecs) > 0 || len(removeExtract) > 0 {
+			extractions, err := mergeExtractions(r.Extract, extractSpecs, removeExtract)
+			if err != nil {
+				return err
+			}
+			updates.Extract = &extractions
+		}
+		if cmd.Flags().Changed("order") {
+			order, _ := cmd.Flags().GetInt("order")
+			updates.Order = &order
+		}
+		if cmd.Flags().Changed("schema") {
+			responseSchema, _ := cmd.Flags().GetString("schema")
+			responseSchema, err := resolveSchema(responseSchema)
+			if err != nil {
+				return err
+			}
+			updates.ResponseSchema = &responseSchema
+		}
+		ignorePaths, _ := cmd.Flags().GetStringArray("snapshot-ignore")
+		removeIgnore, _ := cmd.Flags().GetStringArray("remove-snapshot-ignore")
+		if len(ignorePaths) > 0 || len(removeIgnore) > 0 {
+			snapshotIgnore, err := mergeSnapshotIgnore(r.SnapshotIgnore, ignorePaths, removeIgnore)
+			if err != nil {
+				return err
+			}
+			updates.SnapshotIgnore = &snapshotIgnore
+		}
+		example := r.Example
+		if clearExample, _ := cmd.Flags().GetBool("clear-example"); clearExample {
+			example = route.Example{}
+		}
+		exampleChanged, err := applyExampleFlags(cmd, &example)
+		if err != nil {
+			return err
+		}
+		if exampleChanged || cmd.Flags().Changed("clear-example") {
+			updates.Example = &example
+		}
 		if description != "" {
 			updates.Description = &description
 		}
@@ -166,16 +352,22 @@ func init() {
 	if err := routeAddCmd.MarkFlagRequired("project"); err != nil {
 		panic(err)
 	}
-	routeAddCmd.Flags().StringP("method", "m", "", "HTTP method: GET, POST, PUT PATCH, DELETE (required)")
-	if err := routeAddCmd.MarkFlagRequired("method"); err != nil {
-		panic(err)
-	}
-	routeAddCmd.Flags().StringP("path", "", "", "Route path (required)")
-	if err := routeAddCmd.MarkFlagRequired("path"); err != nil {
-		panic(err)
-	}
+	routeAddCmd.Flags().StringP("method", "m", "", "HTTP method: GET, POST, PUT PATCH, DELETE (required unless --from-curl)")
+	routeAddCmd.Flags().StringP("path", "", "", "Route path (required unless --from-curl)")
+	routeAddCmd.Flags().String("from-curl", "", "Create the route from a curl command")
 	routeAddCmd.Flags().StringP("name", "n", "", "Route name (default = Method + Path)")
 	routeAddCmd.Flags().StringP("description", "d", "", "Route description (optional)")
+	routeAddCmd.Flags().StringArray("param", nil, "Default path parameter value as name=value (repeatable)")
+	routeAddCmd.Flags().StringArrayP("header", "H", nil, "Request header as \"Name: value\" (repeatable)")
+	routeAddCmd.Flags().String("content-type", "", "Request content type (default application/json when a body is set)")
+	routeAddCmd.Flags().String("body", "", "Request body, inline or @file.json")
+	routeAddCmd.Flags().Bool("public", false, "Send without the project's auth")
+	addExpectFlags(routeAddCmd)
+	routeAddCmd.Flags().StringArray("extract", nil, "Capture a response value as name=$.json.path, name=header:Name or name=regex:pattern (repeatable)")
+	routeAddCmd.Flags().Int("order", 0, "Position when the project's routes run (default = after existing routes)")
+	routeAddCmd.Flags().String("schema", "", "JSON Schema the response body must satisfy: inline JSON, a file, or spec.yaml#/components/schemas/Name")
+	routeAddCmd.Flags().StringArray("snapshot-ignore", nil, "JSON path left out of snapshot comparison, e.g. $.updated_at or $.items[*].id (repeatable)")
+	addExampleFlags(routeAddCmd)
 
 	// List command flags
 	routeListCmd.Flags().StringP("project", "p", "", "Project name (required)")
@@ -197,6 +389,22 @@ func init() {
 	routeUpdateCmd.Flags().StringP("path", "", "", "Route path (optional)")
 	routeUpdateCmd.Flags().StringP("description", "d", "", "Route description (optional)")
 	routeUpdateCmd.Flags().StringP("rename", "", "", "Rename route (optional)")
+	routeUpdateCmd.Flags().StringArray("param", nil, "Default path parameter value as name=value (repeatable)")
+	routeUpdateCmd.Flags().StringArrayP("header", "H", nil, "Set a request header as \"Name: value\" (repeatable)")
+	routeUpdateCmd.Flags().StringArray("remove-header", nil, "Remove a request header by name (repeatable)")
+	routeUpdateCmd.Flags().String("content-type", "", "Request content type")
+	routeUpdateCmd.Flags().String("body", "", "Request body, inline or @file.json (empty string clears it)")
+	routeUpdateCmd.Flags().Bool("public", false, "Send without the project's auth (--public=false to re-enable)")
+	addExpectFlags(routeUpdateCmd)
+	routeUpdateCmd.Flags().Bool("clear-expect", false, "Remove existing expectations before applying new ones")
+	routeUpdateCmd.Flags().StringArray("extract", nil, "Add or replace a capture as name=$.json.path, name=header:Name or name=regex:pattern (repeatable)")
+	routeUpdateCmd.Flags().StringArray("remove-extract", nil, "Remove a capture by variable name (repeatable)")
+	routeUpdateCmd.Flags().Int("order", 0, "Position when the project's routes run")
+	routeUpdateCmd.Flags().String("schema", "", "JSON Schema the response body must satisfy: inline JSON, a file, or spec.yaml#/components/schemas/Name (empty string clears it)")
+	routeUpdateCmd.Flags().StringArray("snapshot-ignore", nil, "Add a JSON path left out of snapshot comparison (repeatable)")
+	routeUpdateCmd.Flags().StringArray("remove-snapshot-ignore", nil, "Stop ignoring a JSON path in snapshot comparison (repeatable)")
+	addExampleFlags(routeUpdateCmd)
+	routeUpdateCmd.Flags().Bool("clear-example", false, "Remove the existing mock response before applying new values")
 
 	// Delete command flags
 	routeDeleteCmd.Flags().StringP("project", "p", "", "Project name (required)")
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/report"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/raworiginal/goapi/internal/suite"
+	"github.com/spf13/cobra"
+)
+
+var suiteCmd = &cobra.Command{
+	Use:   "suite",
+	Short: "Manage ordered multi-step test suites",
+}
+
+var suiteCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an empty suite in a project",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		name, _ := cmd.Flags().GetString("name")
+		description, _ := cmd.Flags().GetString("description")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		s := &suite.Suite{ProjectID: p.ID, Name: name, Description: description}
+		if err := storage.CreateSuite(s); err != nil {
+			return fmt.Errorf("failed to create suite: %w", err)
+		}
+		fmt.Printf("Suite '%s' created in project '%s'\n", s.Name, p.Name)
+		return nil
+	},
+}
+
+var suiteAddStepCmd = &cobra.Command{
+	Use:   "add-step",
+	Short: "Add a route as a step of a suite",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		suiteName, _ := cmd.Flags().GetString("suite")
+		routeName, _ := cmd.Flags().GetString("route")
+		name, _ := cmd.Flags().GetString("name")
+		position, _ := cmd.Flags().GetInt("position")
+		paramPairs, _ := cmd.Flags().GetStringArray("param")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		s, err := storage.GetSuiteByName(p.ID, suiteName)
+		if err != nil {
+			return fmt.Errorf("suite '%s' not found in project '%s': %w", suiteName, p.Name, err)
+		}
+		r, err := storage.GetRouteByName(p.ID, routeName)
+		if err != nil {
+			return fmt.Errorf("route '%s' not found in project '%s': %w", routeName, p.Name, err)
+		}
+		params, err := parseKeyValues(paramPairs)
+		if err != nil {
+			return err
+		}
+		step := &suite.Step{SuiteID: s.ID, Position: position, RouteID: r.ID, Name: name, Params: params}
+		if cmd.Flags().Changed("body") {
+			bodyFlag, _ := cmd.Flags().GetString("body")
+			body, err := readBody(bodyFlag)
+			if err != nil {
+				return err
+			}
+			step.Body = &body
+		}
+		if _, err := applyExpectFlags(cmd, &step.Expect); err != nil {
+			return err
+		}
+		if err := step.Validate(r); err != nil {
+			return err
+		}
+		if err := storage.AddStep(step); err != nil {
+			return fmt.Errorf("failed to add step: %w", err)
+		}
+		fmt.Printf("Added step %d (%s) to suite '%s'\n", step.Position, r.Name, s.Name)
+		return nil
+	},
+}
+
+var suiteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the suites of a project, or the steps of one suite",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		suiteName, _ := cmd.Flags().GetString("suite")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if suiteName == "" {
+			suites, err := storage.ListSuitesByProject(p.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list suites: %w", err)
+			}
+			if len(suites) == 0 {
+				fmt.Printf("No suites found for project '%s'\n", p.Name)
+				return nil
+			}
+			if _, err := fmt.Fprintln(w, "Name\tSteps\tDescription"); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+			for _, s := range suites {
+				if _, err := fmt.Fprintf(w, "%s\t%d\t%s\n", s.Name, len(s.Steps), s.Description); err != nil {
+					return fmt.Errorf("failed to write table line: %w", err)
+				}
+			}
+		} else {
+			s, err := storage.GetSuiteByName(p.ID, suiteName)
+			if err != nil {
+				return fmt.Errorf("suite '%s' not found in project '%s': %w", suiteName, p.Name, err)
+			}
+			routes, err := routesByID(p.ID)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, "Step\tName\tRoute\tMethod\tPath\tOverrides"); err != nil {
+				return fmt.Errorf("failed to write header: %w", err)
+			}
+			for _, step := range s.Steps {
+				routeName, method, path := "(deleted)", "", ""
+				if r, ok := routes[step.RouteID]; ok {
+					routeName, method, path = r.Name, string(r.Method), r.Path
+				}
+				if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", step.Position, step.Name, routeName, method, path, step.Overrides()); err != nil {
+					return fmt.Errorf("failed to write table line: %w", err)
+				}
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return fmt.Errorf("failed to write suites table: %w", err)
+		}
+		return nil
+	},
+}
+
+var suiteRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the steps of a suite in order",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		suiteName, _ := cmd.Flags().GetString("suite")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		envName, _ := cmd.Flags().GetString("env")
+		varPairs, _ := cmd.Flags().GetStringArray("var")
+		outputStr, _ := cmd.Flags().GetString("output")
+		outFile, _ := cmd.Flags().GetString("out-file")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		format, err := report.ParseFormat(outputStr)
+		if err != nil {
+			return err
+		}
+		vars, err := parseKeyValues(varPairs)
+		if err != nil {
+			return err
+		}
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		s, err := storage.GetSuiteByName(p.ID, suiteName)
+		if err != nil {
+			return fmt.Errorf("suite '%s' not found in project '%s': %w", suiteName, p.Name, err)
+		}
+		if len(s.Steps) == 0 {
+			return fmt.Errorf("suite '%s' has no steps (use goapi suite add-step)", s.Name)
+		}
+		routes, err := routesByID(p.ID)
+		if err != nil {
+			return err
+		}
+		steps := make([]*route.Route, 0, len(s.Steps))
+		for _, step := range s.Steps {
+			r, ok := routes[step.RouteID]
+			if !ok {
+				return fmt.Errorf("step %d of suite '%s' references a deleted route", step.Position, s.Name)
+			}
+			steps = append(steps, step.Apply(r))
+		}
+
+		run, err := newRunner(p, timeout)
+		if err != nil {
+			return err
+		}
+		env, err := loadEnvironment(p, envName)
+		if err != nil {
+			return err
+		}
+		if env != nil {
+			run.UseEnvironment(env)
+		}
+		maps.Copy(run.Vars, vars)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		startedAt := time.Now()
+		results := run.RunAll(ctx, steps, 1, failFast)
+		if skipped := len(steps) - len(results); skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Stopped early: %d of %d steps not run\n", skipped, len(steps))
+		}
+		envLabel := ""
+		if env != nil {
+			envLabel = env.Name
+		}
+		rep := report.New(p.Name, envLabel, startedAt, results)
+
+		// Failures are reported through the exit code, not usage help
+		cmd.SilenceUsage = true
+		if err := writeReport(rep, format, outFile); err != nil {
+			return err
+		}
+		if _, err := recordRun(p.ID, s.Name, rep); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to record run history: %v\n", err)
+		}
+		if rep.Failed > 0 {
+			return fmt.Errorf("%d of %d steps failed", rep.Failed, len(results))
+		}
+		return nil
+	},
+}
+
+var suiteDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a suite and its steps",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		suiteName, _ := cmd.Flags().GetString("suite")
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		s, err := storage.GetSuiteByName(p.ID, suiteName)
+		if err != nil {
+			return fmt.Errorf("suite '%s' not found in project '%s': %w", suiteName, p.Name, err)
+		}
+		if err := storage.DeleteSuite(s.ID); err != nil {
+			return fmt.Errorf("failed to delete suite: %w", err)
+		}
+		fmt.Printf("Suite '%s' deleted from project '%s'\n", s.Name, p.Name)
+		return nil
+	},
+}
+
+// routesByID loads the routes of a project keyed by ID
+func routesByID(projectID uint) (map[uint]*route.Route, error) {
+	routes, err := storage.ListRoutesByProject(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routes for project: %w", err)
+	}
+	byID := make(map[uint]*route.Route, len(routes))
+	for _, r := range routes {
+		byID[r.ID] = r
+	}
+	return byID, nil
+}
+
+func init() {
+	rootCmd.AddCommand(suiteCmd)
+	suiteCmd.AddCommand(suiteCreateCmd)
+	suiteCmd.AddCommand(suiteAddStepCmd)
+	suiteCmd.AddCommand(suiteListCmd)
+	suiteCmd.AddCommand(suiteRunCmd)
+	suiteCmd.AddCommand(suiteDeleteCmd)
+
+	for _, c := range []*cobra.Command{suiteCreateCmd, suiteAddStepCmd, suiteListCmd, suiteRunCmd, suiteDeleteCmd} {
+		c.Flags().StringP("project", "p", "", "Project name (required)")
+		if err := c.MarkFlagRequired("project"); err != nil {
+			panic(err)
+		}
+	}
+	for _, c := range []*cobra.Command{suiteAddStepCmd, suiteRunCmd, suiteDeleteCmd} {
+		c.Flags().StringP("suite", "s", "", "Suite name (required)")
+		if err := c.MarkFlagRequired("suite"); err != nil {
+			panic(err)
+		}
+	}
+
+	// Create command flags
+	suiteCreateCmd.Flags().StringP("name", "n", "", "Suite name (required)")
+	if err := suiteCreateCmd.MarkFlagRequired("name"); err != nil {
+		panic(err)
+	}
+	suiteCreateCmd.Flags().StringP("description", "d", "", "Suite description (optional)")
+
+	// Add-step command flags
+	suiteAddStepCmd.Flags().StringP("route", "r", "", "Route name (required)")
+	if err := suiteAddStepCmd.MarkFlagRequired("route"); err != nil {
+		panic(err)
+	}
+	suiteAddStepCmd.Flags().StringP("name", "n", "", "Step label in results (default = route name)")
+	suiteAddStepCmd.Flags().Int("position", 0, "Insert at this position, moving later steps down (default = append)")
+	suiteAddStepCmd.Flags().StringArray("param", nil, "Path parameter value for this step as name=value (repeatable)")
+	suiteAddStepCmd.Flags().String("body", "", "Request body for this step, inline or @file.json")
+	addExpectFlags(suiteAddStepCmd)
+
+	// List command flags
+	suiteListCmd.Flags().StringP("suite", "s", "", "Show the steps of this suite")
+
+	// Run command flags
+	suiteRunCmd.Flags().Duration("timeout", 5*time.Second, "Request timeout")
+	suiteRunCmd.Flags().StringP("env", "e", "", "Environment name (defaults to the project's active environment)")
+	suiteRunCmd.Flags().StringArray("var", nil, "Variable as name=value, overriding the environment (repeatable)")
+	suiteRunCmd.Flags().StringP("output", "o", "table", "Output format: table, json, ndjson, junit")
+	suiteRunCmd.Flags().String("out-file", "", "Write results to a file instead of stdout")
+	suiteRunCmd.Flags().Bool("fail-fast", false, "Stop at the first failing step")
+}
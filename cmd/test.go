@@ -1,27 +1,27 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"maps"
 	"os"
-	"text/tabwriter"
+	"os/signal"
+	"sync"
 	"time"
 
 	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/report"
 	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/runner"
+	"github.com/raworiginal/goapi/internal/snapshot"
 	"github.com/raworiginal/goapi/internal/storage"
 	"github.com/spf13/cobra"
 )
 
-type TestResult struct {
-	RouteID    uint
-	RouteName  string
-	Method     string
-	Path       string
-	StatusCode int
-	Duration   time.Duration
-	Error      string
-}
-
 var testCmd = &cobra.Command{
 	Use:   "test",
 	Short: "Test API routes",
@@ -36,16 +36,79 @@ func init() {
 	testCmd.Flags().StringP("project", "p", "", "Project name (required)")
 	testCmd.Flags().String("route", "", "Route name (optional, tests all if omitted)")
 	testCmd.Flags().Duration("timeout", 5*time.Second, "Request timeout")
+	testCmd.Flags().StringArray("param", nil, "Path parameter value as name=value (repeatable)")
+	testCmd.Flags().StringP("env", "e", "", "Environment name (defaults to the project's active environment)")
+	testCmd.Flags().StringArray("var", nil, "Variable as name=value, overriding the environment (repeatable)")
+	testCmd.Flags().StringP("output", "o", "table", "Output format: table, json, ndjson, junit")
+	testCmd.Flags().String("out-file", "", "Write results to a file instead of stdout")
+	testCmd.Flags().IntP("concurrency", "c", 1, "Number of routes to run at the same time")
+	testCmd.Flags().Bool("fail-fast", false, "Stop at the first failing route, cancelling requests in flight")
+	testCmd.Flags().Bool("update-snapshots", false, "Store each response body as the route's snapshot instead of comparing against it")
 
 	if err := testCmd.MarkFlagRequired("project"); err != nil {
 		panic(err)
 	}
 }
 
+// newRunner creates a runner for p using the project's stored auth
+func newRunner(p *project.Project, timeout time.Duration) (*runner.Runner, error) {
+	a, err := storage.GetAuth(p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load auth: %w", err)
+	}
+	var login *route.Route
+	if a != nil && a.Type == auth.JWT {
+		login, err = storage.GetRoute(a.LoginRouteID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load login route: %w", err)
+		}
+	}
+	return runner.New(p, api.Config{Timeout: timeout}, a, login, storage.SetAuth)
+}
+
+// loadEnvironment returns the named environment of p, or its active one when name is empty
+func loadEnvironment(p *project.Project, name string) (*environment.Environment, error) {
+	if name == "" {
+		env, err := storage.GetActiveEnvironment(p.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load active environment: %w", err)
+		}
+		return env, nil
+	}
+	env, err := storage.GetEnvironmentByName(p.ID, name)
+	if err != nil {
+		return nil, fmt.Errorf("environment '%s' not found in project '%s': %w", name, p.Name, err)
+	}
+	return env, nil
+}
+
 func testRun(cmd *cobra.Command, args []string) error {
 	projectName, _ := cmd.Flags().GetString("project")
 	routeName, _ := cmd.Flags().GetString("route")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
+	paramPairs, _ := cmd.Flags().GetStringArray("param")
+	envName, _ := cmd.Flags().GetString("env")
+	varPairs, _ := cmd.Flags().GetStringArray("var")
+	outputStr, _ := cmd.Flags().GetString("output")
+	outFile, _ := cmd.Flags().GetString("out-file")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	failFast, _ := cmd.Flags().GetBool("fail-fast")
+	updateSnapshots, _ := cmd.Flags().GetBool("update-snapshots")
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	format, err := report.ParseFormat(outputStr)
+	if err != nil {
+		return err
+	}
+	params, err := parseKeyValues(paramPairs)
+	if err != nil {
+		return err
+	}
+	vars, err := parseKeyValues(varPairs)
+	if err != nil {
+		return err
+	}
 
 	p, err := storage.GetProject(projectName)
 	if err != nil {
@@ -64,47 +127,121 @@ func testRun(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to load routes: %w", err)
 		}
 	}
-	config := api.Config{Timeout: timeout}
-	client := api.NewHTTPClient(config)
-
-	var results []TestResult
-	for _, r := range routes {
-		url := p.BaseURL + r.Path
+	run, err := newRunner(p, timeout)
+	if err != nil {
+		return err
+	}
+	env, err := loadEnvironment(p, envName)
+	if err != nil {
+		return err
+	}
+	if env != nil {
+		run.UseEnvironment(env)
+	}
+	run.Params = params
+	maps.Copy(run.Vars, vars)
+	if routeName != "" {
+		// A single route has no earlier routes to supply variables, so an unbound
+		// path parameter is an input error rather than a failed result
+		if _, err := run.Request(routes[0]); err != nil {
+			return fmt.Errorf("failed to build request for route '%s': %w", routes[0].Name, err)
+		}
+	}
+	snapshots, err := newSnapshotChecker(p.ID, updateSnapshots)
+	if err != nil {
+		return err
+	}
+	run.Checks = append(run.Checks, snapshots.check)
 
-		resp, err := client.Do(string(r.Method), url, nil)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	startedAt := time.Now()
+	results := run.RunAll(ctx, routes, concurrency, failFast)
+	if skipped := len(routes) - len(results); skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Stopped early: %d of %d routes not run\n", skipped, len(routes))
+	}
+	if updateSnapshots {
+		fmt.Fprintf(os.Stderr, "Updated %d snapshots\n", snapshots.updated)
+	}
+	envLabel := ""
+	if env != nil {
+		envLabel = env.Name
+	}
+	rep := report.New(p.Name, envLabel, startedAt, results)
 
-		result := TestResult{
-			RouteID:   r.ID,
-			RouteName: r.Name,
-			Method:    string(r.Method),
-			Path:      r.Path,
-		}
-		if err != nil {
-			result.Error = err.Error()
-		} else {
-			result.StatusCode = resp.StatusCode
-			result.Duration = resp.Duration
-		}
-		results = append(results, result)
+	// Failures are reported through the exit code, not usage help
+	cmd.SilenceUsage = true
+	if err := writeReport(rep, format, outFile); err != nil {
+		return err
+	}
+	if _, err := recordRun(p.ID, "", rep); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record run history: %v\n", err)
+	}
+	if rep.Failed > 0 {
+		return fmt.Errorf("%d of %d routes failed", rep.Failed, len(results))
 	}
+	return nil
+}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	if _, err := fmt.Fprintln(w, "ID\tName\tMethod\tPath\tStatus\tDuration"); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+// writeReport writes rep to outFile, or to stdout when outFile is empty
+func writeReport(rep *report.Report, format report.Format, outFile string) error {
+	if outFile == "" {
+		return rep.Write(os.Stdout, format)
 	}
+	f, err := os.Create(outFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := rep.Write(f, format); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	fmt.Printf("Results written to %s (%d passed, %d failed)\n", outFile, rep.Passed, rep.Failed)
+	return nil
+}
+
+// snapshotChecker compares response bodies with the project's stored snapshots, or
+// when updating, replaces the snapshots with the response bodies
+type snapshotChecker struct {
+	projectID uint
+	update    bool
+	snapshots map[uint]*snapshot.Snapshot
+	mu        sync.Mutex
+	updated   int
+}
 
-	for _, r := range results {
-		status := "Error"
-		if r.Error == "" {
-			status = fmt.Sprintf("%d", r.StatusCode)
+func newSnapshotChecker(projectID uint, update bool) (*snapshotChecker, error) {
+	c := &snapshotChecker{projectID: projectID, update: update}
+	if !update {
+		var err error
+		if c.snapshots, err = storage.ListSnapshotsByProject(projectID); err != nil {
+			return nil, fmt.Errorf("failed to load snapshots: %w", err)
 		}
-		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%v\n", r.RouteID, r.RouteName, r.Method, r.Path, status, r.Duration); err != nil {
-			return fmt.Errorf("failed to write table line: %w", err)
+	}
+	return c, nil
+}
+
+// check is a runner.Check; routes without a snapshot are not compared
+func (c *snapshotChecker) check(r *route.Route, resp *api.Response) []assert.Result {
+	if !c.update {
+		s := c.snapshots[r.ID]
+		if s == nil {
+			return nil
 		}
+		return []assert.Result{s.Compare(resp.Body, r.SnapshotIgnore)}
 	}
-	if err := w.Flush(); err != nil {
-		return fmt.Errorf("failed to flush table: %w", err)
+	body, err := snapshot.Normalize(resp.Body, r.SnapshotIgnore)
+	if err != nil {
+		return []assert.Result{{Name: "snapshot", Message: err.Error()}}
 	}
-
-	return nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := storage.SaveSnapshot(&snapshot.Snapshot{ProjectID: c.projectID, RouteID: r.ID, Body: body}); err != nil {
+		return []assert.Result{{Name: "snapshot", Message: "failed to save snapshot: " + err.Error()}}
+	}
+	c.updated++
+	return []assert.Result{{Name: "snapshot", Passed: true, Message: "updated"}}
 }
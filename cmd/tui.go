@@ -0,0 +1,37 @@
+package main
+
+import (
+	"time"
+
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/runner"
+	"github.com/raworiginal/goapi/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse projects and run routes interactively",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		return tui.Run(func(p *project.Project) (*runner.Runner, error) {
+			run, err := newRunner(p, timeout)
+			if err != nil {
+				return nil, err
+			}
+			env, err := loadEnvironment(p, "")
+			if err != nil {
+				return nil, err
+			}
+			if env != nil {
+				run.UseEnvironment(env)
+			}
+			return run, nil
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().Duration("timeout", 5*time.Second, "Request timeout")
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/openapi"
+	"github.com/raworiginal/goapi/internal/runner"
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/raworiginal/goapi/internal/verify"
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check a project's routes against an OpenAPI spec",
+	Long: `Check a project's routes against an OpenAPI spec.
+
+Routes are matched to the spec's operations by method and path. Routes whose
+path is not in the spec, routes using a method the spec does not declare for
+their path, and operations without a route are reported as drift. The routes
+are then run, and each response must have a status the operation declares and
+a body matching the declared schema. The report ends with the share of the
+spec's operations that were exercised.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectName, _ := cmd.Flags().GetString("project")
+		specFile, _ := cmd.Flags().GetString("spec")
+		noRun, _ := cmd.Flags().GetBool("no-run")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		envName, _ := cmd.Flags().GetString("env")
+		varPairs, _ := cmd.Flags().GetStringArray("var")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		output, _ := cmd.Flags().GetString("output")
+		outFile, _ := cmd.Flags().GetString("out-file")
+		if output != "table" && output != "json" {
+			return fmt.Errorf("invalid output format: %s. Valid formats are: table, json", output)
+		}
+		if concurrency < 1 {
+			return fmt.Errorf("--concurrency must be at least 1")
+		}
+		vars, err := parseKeyValues(varPairs)
+		if err != nil {
+			return err
+		}
+
+		doc, err := openapi.Load(specFile)
+		if err != nil {
+			return fmt.Errorf("failed to load spec: %w", err)
+		}
+		p, err := storage.GetProject(projectName)
+		if err != nil {
+			return fmt.Errorf("failed to get project '%s': %w", projectName, err)
+		}
+		routes, err := storage.ListRoutesByProject(p.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list routes for project: %w", err)
+		}
+		env, err := loadEnvironment(p, envName)
+		if err != nil {
+			return err
+		}
+		baseURL := p.BaseURL
+		if env != nil && env.BaseURL != "" {
+			baseURL = env.BaseURL
+		}
+		comparison := verify.Compare(doc, routes, verify.PathPrefix(baseURL, doc.Servers))
+
+		startedAt := time.Now()
+		var results []runner.TestResult
+		if !noRun {
+			run, err := newRunner(p, timeout)
+			if err != nil {
+				return err
+			}
+			if env != nil {
+				run.UseEnvironment(env)
+			}
+			maps.Copy(run.Vars, vars)
+			run.Checks = append(run.Checks, comparison.Check)
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			results = run.RunAll(ctx, routes, concurrency, false)
+		}
+		rep := verify.NewReport(p.Name, specFile, startedAt, comparison, results)
+
+		// Drift and failures are reported through the exit code, not usage help
+		cmd.SilenceUsage = true
+		out := os.Stdout
+		if outFile != "" {
+			if out, err = os.Create(outFile); err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+		}
+		if output == "json" {
+			err = rep.WriteJSON(out)
+		} else {
+			err = rep.WriteTable(out)
+		}
+		if outFile != "" {
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+		if rep.Drift() > 0 || rep.Failed > 0 {
+			return fmt.Errorf("verification failed: %d drift finding(s), %d route(s) failed", rep.Drift(), rep.Failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringP("project", "p", "", "Project name (required)")
+	if err := verifyCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	verifyCmd.Flags().String("spec", "", "OpenAPI or Swagger document, YAML or JSON (required)")
+	if err := verifyCmd.MarkFlagRequired("spec"); err != nil {
+		panic(err)
+	}
+	verifyCmd.Flags().Bool("no-run", false, "Only compare the routes with the spec, without sending requests")
+	verifyCmd.Flags().Duration("timeout", 5*time.Second, "Request timeout")
+	verifyCmd.Flags().StringP("env", "e", "", "Environment name (defaults to the project's active environment)")
+	verifyCmd.Flags().StringArray("var", nil, "Variable as name=value, overriding the environment (repeatable)")
+	verifyCmd.Flags().IntP("concurrency", "c", 1, "Number of routes to run at the same time")
+	verifyCmd.Flags().StringP("output", "o", "table", "Output format: table, json")
+	verifyCmd.Flags().String("out-file", "", "Write the report to a file instead of stdout")
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/raworiginal/goapi/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init [dir]",
+	Short: "Create a workspace with its own database in a directory",
+	Long: `Create a .goapi workspace in a directory, the current one by default.
+
+Commands run in the directory or below it use the workspace's database instead
+of ~/.config/goapi/goapi.db, unless --db or GOAPI_DB is set. The database is
+ignored by git; keep the project in a goapi.yaml manifest next to it instead.`,
+	Args: cobra.MaximumNArgs(1),
+	// the database is opened once the workspace exists
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		workspace, err := filepath.Abs(filepath.Join(dir, storage.WorkspaceDir))
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(workspace); err == nil {
+			return fmt.Errorf("workspace already exists: %s", workspace)
+		}
+		if err := os.MkdirAll(workspace, 0o755); err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(workspace, ".gitignore"), []byte("goapi.db*\n"), 0o644); err != nil {
+			return fmt.Errorf("failed to create workspace: %w", err)
+		}
+		if err := storage.InitDB(filepath.Join(workspace, "goapi.db")); err != nil {
+			return fmt.Errorf("failed to create database: %w", err)
+		}
+		fmt.Printf("Initialized workspace in %s\n", workspace)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
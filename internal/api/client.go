@@ -11,16 +11,51 @@ import (
 
 type Response struct {
 	StatusCode int
+	Headers    http.Header
 	Body       []byte
 	Duration   time.Duration
 }
 
 type Config struct {
 	Timeout time.Duration
+	Auth    Authenticator
+}
+
+// Authenticator adds credentials to outgoing requests
+type Authenticator interface {
+	Apply(req *http.Request) error
+}
+
+// Request describes a single HTTP call
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+	NoAuth  bool // skip the configured Authenticator
+}
+
+// HTTPRequest converts r to a standard library request, applying auth unless r.NoAuth is set
+func (r *Request) HTTPRequest(ctx context.Context, auth Authenticator) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, r.Method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range r.Headers {
+		req.Header.Set(name, value)
+	}
+	if auth != nil && !r.NoAuth {
+		if err := auth.Apply(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
 }
 
 type Client interface {
 	Do(method, url string, body []byte) (*Response, error)
+	Send(r *Request) (*Response, error)
+	SendContext(ctx context.Context, r *Request) (*Response, error)
 }
 
 type HTTPClient struct {
@@ -32,12 +67,21 @@ func NewHTTPClient(config Config) Client {
 }
 
 func (c *HTTPClient) Do(method, url string, body []byte) (*Response, error) {
+	return c.Send(&Request{Method: method, URL: url, Body: body})
+}
+
+func (c *HTTPClient) Send(r *Request) (*Response, error) {
+	return c.SendContext(context.Background(), r)
+}
+
+// SendContext sends r, aborting it when ctx is cancelled or the configured timeout passes
+func (c *HTTPClient) SendContext(ctx context.Context, r *Request) (*Response, error) {
 	// Create a context with Timeout
-	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
 	defer cancel()
 
 	// Create the HTTP Request
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	req, err := r.HTTPRequest(ctx, c.config.Auth)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +111,7 @@ func (c *HTTPClient) Do(method, url string, body []byte) (*Response, error) {
 	// Return the respose
 	return &Response{
 		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
 		Body:       respBody,
 		Duration:   duration,
 	}, nil
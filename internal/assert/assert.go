@@ -0,0 +1,224 @@
+// Package assert evaluates route expectations against API responses
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+)
+
+type Op string
+
+const (
+	Equals Op = "eq"
+	Match  Op = "regex"
+	Exists Op = "exists"
+)
+
+// Expectation is the set of checks a route's response must satisfy
+type Expectation struct {
+	Status      string            `json:"status,omitempty"`       // 200, 2xx or 200-299
+	Headers     map[string]string `json:"headers,omitempty"`      // header name to value regex, empty to require presence only
+	Body        []BodyCheck       `json:"body,omitempty"`         // JSON path checks on the response body
+	MaxDuration time.Duration     `json:"max_duration,omitempty"` // zero for no limit
+}
+
+// BodyCheck asserts on the value at a JSON path of the response body
+type BodyCheck struct {
+	Path  string `json:"path"`
+	Op    Op     `json:"op"`
+	Value string `json:"value,omitempty"`
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// IsZero reports whether the expectation has no checks
+func (e *Expectation) IsZero() bool {
+	return e == nil || (e.Status == "" && len(e.Headers) == 0 && len(e.Body) == 0 && e.MaxDuration == 0)
+}
+
+// ParseStatus validates a status expectation: an exact code, a class like 2xx, or a range like 200-299
+func ParseStatus(s string) (min, max int, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) == 3 && strings.HasSuffix(s, "xx") && s[0] >= '1' && s[0] <= '5' {
+		class := int(s[0]-'0') * 100
+		return class, class + 99, nil
+	}
+	if lo, hi, ok := strings.Cut(s, "-"); ok {
+		min, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		max, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || min > max {
+			return 0, 0, fmt.Errorf("invalid status range: %s", s)
+		}
+		return min, max, nil
+	}
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 100 || code > 599 {
+		return 0, 0, fmt.Errorf("invalid status: %s. Use a code (200), class (2xx) or range (200-299)", s)
+	}
+	return code, code, nil
+}
+
+// ParseBodyCheck parses expressions of the form "$.path exists", "$.path == value" and "$.path ~ regex"
+func ParseBodyCheck(expr string) (BodyCheck, error) {
+	expr = strings.TrimSpace(expr)
+	if path, ok := strings.CutSuffix(expr, " exists"); ok {
+		return newBodyCheck(path, Exists, "")
+	}
+	if path, value, ok := strings.Cut(expr, " == "); ok {
+		return newBodyCheck(path, Equals, value)
+	}
+	if path, value, ok := strings.Cut(expr, " ~ "); ok {
+		return newBodyCheck(path, Match, value)
+	}
+	return BodyCheck{}, fmt.Errorf("invalid body check '%s': expected \"<path> exists\", \"<path> == <value>\" or \"<path> ~ <regex>\"", expr)
+}
+
+func newBodyCheck(path string, op Op, value string) (BodyCheck, error) {
+	path = strings.TrimSpace(path)
+	if _, err := jsonpath.Parse(path); err != nil {
+		return BodyCheck{}, err
+	}
+	if op == Match {
+		if _, err := regexp.Compile(value); err != nil {
+			return BodyCheck{}, fmt.Errorf("invalid regex '%s': %w", value, err)
+		}
+	}
+	return BodyCheck{Path: path, Op: op, Value: value}, nil
+}
+
+// String renders the check in the form accepted by ParseBodyCheck
+func (c BodyCheck) String() string {
+	switch c.Op {
+	case Exists:
+		return c.Path + " exists"
+	case Match:
+		return c.Path + " ~ " + c.Value
+	default:
+		return c.Path + " == " + c.Value
+	}
+}
+
+// Evaluate runs every check of the expectation against resp
+func (e *Expectation) Evaluate(resp *api.Response) []Result {
+	if e.IsZero() {
+		return nil
+	}
+	var results []Result
+	if e.Status != "" {
+		results = append(results, e.checkStatus(resp.StatusCode))
+	}
+	for _, name := range slices.Sorted(maps.Keys(e.Headers)) {
+		results = append(results, checkHeader(resp.Headers, name, e.Headers[name]))
+	}
+	for _, c := range e.Body {
+		results = append(results, c.evaluate(resp.Body))
+	}
+	if e.MaxDuration > 0 {
+		r := Result{Name: "duration <= " + e.MaxDuration.String(), Passed: resp.Duration <= e.MaxDuration}
+		if !r.Passed {
+			r.Message = fmt.Sprintf("took %v", resp.Duration)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Passed reports whether all results passed
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *Expectation) checkStatus(code int) Result {
+	r := Result{Name: "status " + e.Status}
+	min, max, err := ParseStatus(e.Status)
+	if err != nil {
+		r.Message = err.Error()
+		return r
+	}
+	r.Passed = code >= min && code <= max
+	if !r.Passed {
+		r.Message = fmt.Sprintf("got status %d", code)
+	}
+	return r
+}
+
+func checkHeader(headers http.Header, name, pattern string) Result {
+	r := Result{Name: "header " + name}
+	values, ok := headers[http.CanonicalHeaderKey(name)]
+	if !ok {
+		r.Message = "header missing"
+		return r
+	}
+	if pattern == "" {
+		r.Passed = true
+		return r
+	}
+	r.Name += " ~ " + pattern
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		r.Message = fmt.Sprintf("invalid regex: %v", err)
+		return r
+	}
+	for _, v := range values {
+		if re.MatchString(v) {
+			r.Passed = true
+			return r
+		}
+	}
+	r.Message = fmt.Sprintf("got %q", strings.Join(values, ", "))
+	return r
+}
+
+func (c BodyCheck) evaluate(body []byte) Result {
+	r := Result{Name: c.String()}
+	value, err := jsonpath.LookupBytes(body, c.Path)
+	if err != nil {
+		if errors.Is(err, jsonpath.ErrNotFound) {
+			r.Message = "path not found"
+		} else {
+			r.Message = err.Error()
+		}
+		return r
+	}
+	got := jsonpath.String(value)
+	switch c.Op {
+	case Exists:
+		r.Passed = true
+	case Equals:
+		r.Passed = got == c.Value
+	case Match:
+		re, err := regexp.Compile(c.Value)
+		if err != nil {
+			r.Message = fmt.Sprintf("invalid regex: %v", err)
+			return r
+		}
+		r.Passed = re.MatchString(got)
+	default:
+		r.Message = fmt.Sprintf("unknown operator: %s", c.Op)
+		return r
+	}
+	if !r.Passed {
+		r.Message = fmt.Sprintf("got %s", got)
+	}
+	return r
+}
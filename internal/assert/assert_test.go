@@ -0,0 +1,89 @@
+package assert
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := map[string][2]int{
+		"200":     {200, 200},
+		"2xx":     {200, 299},
+		"400-404": {400, 404},
+	}
+	for input, want := range tests {
+		min, max, err := ParseStatus(input)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", input, err)
+			continue
+		}
+		if min != want[0] || max != want[1] {
+			t.Errorf("%s: expected %v, got [%d %d]", input, want, min, max)
+		}
+	}
+	for _, input := range []string{"abc", "6xx", "300-200", "99"} {
+		if _, _, err := ParseStatus(input); err == nil {
+			t.Errorf("%s: expected error, got nil", input)
+		}
+	}
+}
+
+func TestParseBodyCheck(t *testing.T) {
+	tests := map[string]BodyCheck{
+		"$.id exists":       {Path: "$.id", Op: Exists},
+		"$.name == Ada":     {Path: "$.name", Op: Equals, Value: "Ada"},
+		"$.email ~ ^.+@.+$": {Path: "$.email", Op: Match, Value: "^.+@.+$"},
+	}
+	for input, want := range tests {
+		got, err := ParseBodyCheck(input)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", input, want, got)
+		}
+	}
+	if _, err := ParseBodyCheck("$.id"); err == nil {
+		t.Error("expected error for check without operator")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	// Arrange: an expectation with one failing check per kind
+	e := &Expectation{
+		Status:  "2xx",
+		Headers: map[string]string{"Content-Type": "json", "X-Trace": ""},
+		Body: []BodyCheck{
+			{Path: "$.id", Op: Equals, Value: "7"},
+			{Path: "$.name", Op: Match, Value: "^B"},
+		},
+		MaxDuration: 100 * time.Millisecond,
+	}
+	resp := &api.Response{
+		StatusCode: 201,
+		Headers:    http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"id": 7, "name": "Ada"}`),
+		Duration:   200 * time.Millisecond,
+	}
+
+	// Act
+	results := e.Evaluate(resp)
+
+	// Assert: status, content type and id pass; trace header, name and duration fail
+	want := []bool{true, true, false, true, false, false}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %d: %+v", len(want), len(results), results)
+	}
+	for i, r := range results {
+		if r.Passed != want[i] {
+			t.Errorf("%s: expected passed=%v, got %v (%s)", r.Name, want[i], r.Passed, r.Message)
+		}
+	}
+	if Passed(results) {
+		t.Error("expected overall failure")
+	}
+}
@@ -0,0 +1,166 @@
+// Package auth handles project authentication profiles
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Type string
+
+const (
+	Bearer Type = "bearer"
+	Basic  Type = "basic"
+	APIKey Type = "apikey"
+	JWT    Type = "jwt" // bearer token obtained from a login route
+)
+
+// Location is where an API key is sent
+type Location string
+
+const (
+	InHeader Location = "header"
+	InQuery  Location = "query"
+)
+
+// Profile is the authentication applied to every request of a project
+type Profile struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ProjectID    uint      `gorm:"uniqueIndex" json:"project_id"`
+	Type         Type      `json:"type"`
+	Token        string    `json:"token,omitempty"`
+	Username     string    `json:"username,omitempty"`
+	Password     string    `json:"password,omitempty"`
+	KeyName      string    `json:"key_name,omitempty"`
+	KeyValue     string    `json:"key_value,omitempty"`
+	KeyIn        Location  `json:"key_in,omitempty"`
+	LoginRouteID uint      `json:"login_route_id,omitempty"`
+	TokenPath    string    `json:"token_path,omitempty"`
+	TokenExpiry  time.Time `json:"token_expiry"` // of the cached Token, zero if unknown
+	DateUpdated  time.Time `gorm:"autoUpdateTime" json:"date_updated"`
+}
+
+func (Profile) TableName() string {
+	return "auth_profiles"
+}
+
+func ParseType(s string) (Type, error) {
+	switch strings.ToLower(s) {
+	case "bearer":
+		return Bearer, nil
+	case "jwt":
+		return JWT, nil
+	case "basic":
+		return Basic, nil
+	case "apikey", "api-key":
+		return APIKey, nil
+	default:
+		return "", fmt.Errorf("invalid auth type: %s. Valid types are: bearer, basic, apikey, jwt", s)
+	}
+}
+
+func ParseLocation(s string) (Location, error) {
+	switch strings.ToLower(s) {
+	case "", "header":
+		return InHeader, nil
+	case "query":
+		return InQuery, nil
+	default:
+		return "", fmt.Errorf("invalid API key location: %s. Valid locations are: header, query", s)
+	}
+}
+
+// Validate checks that the fields required by the profile type are set
+func (p *Profile) Validate() error {
+	switch p.Type {
+	case Bearer:
+		if p.Token == "" {
+			return fmt.Errorf("bearer auth requires a token")
+		}
+	case Basic:
+		if p.Username == "" {
+			return fmt.Errorf("basic auth requires a username")
+		}
+	case APIKey:
+		if p.KeyName == "" || p.KeyValue == "" {
+			return fmt.Errorf("apikey auth requires a key name and value")
+		}
+		if p.KeyIn != InHeader && p.KeyIn != InQuery {
+			return fmt.Errorf("invalid API key location: %s", p.KeyIn)
+		}
+	case JWT:
+		if p.LoginRouteID == 0 || p.TokenPath == "" {
+			return fmt.Errorf("jwt auth requires a login route and a token path")
+		}
+	default:
+		return fmt.Errorf("invalid auth type: %s", p.Type)
+	}
+	return nil
+}
+
+// Apply adds the profile's credentials to req
+func (p *Profile) Apply(req *http.Request) error {
+	switch p.Type {
+	case Bearer:
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	case JWT:
+		if !p.TokenValid(time.Now()) {
+			return fmt.Errorf("no valid JWT cached: log in first")
+		}
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	case Basic:
+		req.SetBasicAuth(p.Username, p.Password)
+	case APIKey:
+		if p.KeyIn == InQuery {
+			q := req.URL.Query()
+			q.Set(p.KeyName, p.KeyValue)
+			req.URL.RawQuery = q.Encode()
+		} else {
+			req.Header.Set(p.KeyName, p.KeyValue)
+		}
+	default:
+		return fmt.Errorf("invalid auth type: %s", p.Type)
+	}
+	return nil
+}
+
+// TokenValid reports whether the cached token can still be used at now
+func (p *Profile) TokenValid(now time.Time) bool {
+	if p.Token == "" {
+		return false
+	}
+	return p.TokenExpiry.IsZero() || now.Add(expirySkew).Before(p.TokenExpiry)
+}
+
+// Summary describes the profile with secrets masked
+func (p *Profile) Summary() string {
+	switch p.Type {
+	case Bearer:
+		return fmt.Sprintf("bearer token %s", mask(p.Token))
+	case Basic:
+		return fmt.Sprintf("basic auth as %s (password %s)", p.Username, mask(p.Password))
+	case APIKey:
+		return fmt.Sprintf("API key %s=%s in %s", p.KeyName, mask(p.KeyValue), p.KeyIn)
+	case JWT:
+		summary := fmt.Sprintf("JWT from login route #%d (token at %s)", p.LoginRouteID, p.TokenPath)
+		if p.Token != "" {
+			summary += fmt.Sprintf(", cached token %s", mask(p.Token))
+			if !p.TokenExpiry.IsZero() {
+				summary += fmt.Sprintf(" expiring %s", p.TokenExpiry.Local().Format("2006-01-02 15:04"))
+			}
+		}
+		return summary
+	default:
+		return string(p.Type)
+	}
+}
+
+// mask hides all but the last four characters of a secret
+func mask(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return strings.Repeat("*", len(secret)-4) + secret[len(secret)-4:]
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestApplyBearer(t *testing.T) {
+	p := &Profile{Type: Bearer, Token: "abc"}
+	req, _ := http.NewRequest("GET", "http://example.com/users", nil)
+
+	if err := p.Apply(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("expected 'Bearer abc', got '%s'", got)
+	}
+}
+
+func TestApplyAPIKeyQuery(t *testing.T) {
+	p := &Profile{Type: APIKey, KeyName: "api_key", KeyValue: "s3cret", KeyIn: InQuery}
+	req, _ := http.NewRequest("GET", "http://example.com/users?page=2", nil)
+
+	if err := p.Apply(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := req.URL.Query().Get("api_key"); got != "s3cret" {
+		t.Errorf("expected api_key=s3cret, got '%s'", got)
+	}
+	if got := req.URL.Query().Get("page"); got != "2" {
+		t.Errorf("expected existing query to be kept, got page='%s'", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (&Profile{Type: Basic}).Validate(); err == nil {
+		t.Error("expected error for basic auth without username")
+	}
+	if err := (&Profile{Type: APIKey, KeyName: "k", KeyValue: "v", KeyIn: InHeader}).Validate(); err != nil {
+		t.Errorf("expected valid apikey profile, got %v", err)
+	}
+}
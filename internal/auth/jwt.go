@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expirySkew treats tokens as expired slightly early so they don't lapse mid-request
+const expirySkew = 30 * time.Second
+
+// ParseExpiry decodes the exp claim of a JWT without verifying its signature.
+// It returns the zero time if the token has no exp claim.
+func ParseExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("invalid JWT claims: %w", err)
+	}
+	if claims.Exp == nil {
+		return time.Time{}, nil
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid JWT exp claim: %w", err)
+	}
+	return time.Unix(int64(exp), 0), nil
+}
+
+// Session authenticates requests with a JWT profile, logging in when the
+// cached token is missing or expired
+type Session struct {
+	Profile *Profile
+	Login   func() (string, error) // performs the login request and returns the token
+	Save    func(p *Profile) error // persists the refreshed token, optional
+
+	mu sync.Mutex
+}
+
+// Apply adds a valid bearer token to req, logging in first if needed
+func (s *Session) Apply(req *http.Request) error {
+	token, err := s.Token()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns the cached token, refreshing it when it has expired
+func (s *Session) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Profile.TokenValid(time.Now()) {
+		return s.Profile.Token, nil
+	}
+	return s.refresh()
+}
+
+// TokenVariable is the shell variable printed in place of a token that has not
+// been fetched
+const TokenVariable = "$TOKEN"
+
+// Cached returns an authenticator that applies the cached token, or TokenVariable
+// when there is no valid one, without ever logging in
+func (s *Session) Cached() *CachedToken {
+	return &CachedToken{session: s}
+}
+
+// CachedToken applies a session's token without refreshing it
+type CachedToken struct {
+	session *Session
+}
+
+func (c *CachedToken) Apply(req *http.Request) error {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+	token := TokenVariable
+	if c.session.Profile.TokenValid(time.Now()) {
+		token = c.session.Profile.Token
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token if it still matches token, so the next request logs in again
+func (s *Session) Invalidate(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Profile.Token == token {
+		s.Profile.Token = ""
+		s.Profile.TokenExpiry = time.Time{}
+	}
+}
+
+func (s *Session) refresh() (string, error) {
+	token, err := s.Login()
+	if err != nil {
+		return "", fmt.Errorf("login failed: %w", err)
+	}
+	if token == "" {
+		return "", fmt.Errorf("login failed: empty token")
+	}
+	// opaque tokens are allowed; they are kept until a request is rejected
+	expiry, _ := ParseExpiry(token)
+	s.Profile.Token = token
+	s.Profile.TokenExpiry = expiry
+	if s.Save != nil {
+		if err := s.Save(s.Profile); err != nil {
+			return "", fmt.Errorf("failed to cache token: %w", err)
+		}
+	}
+	return token, nil
+}
@@ -0,0 +1,286 @@
+// Package curl parses curl command lines into requests and renders requests as curl commands
+package curl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/auth"
+)
+
+// Command is the request described by a curl command line
+type Command struct {
+	Method   string
+	URL      string
+	Headers  http.Header
+	Body     string
+	Username string
+	Password string
+}
+
+// flags that take no value and can be ignored
+var switches = []string{
+	"-s", "--silent", "-S", "--show-error", "-L", "--location", "-k", "--insecure",
+	"-v", "--verbose", "-i", "--include", "--compressed", "-f", "--fail", "-g", "--globoff",
+}
+
+// flags that take a value which does not affect the request
+var ignored = []string{"-o", "--output", "-w", "--write-out", "-m", "--max-time", "--connect-timeout", "--retry"}
+
+// Parse reads a curl command line. The leading "curl" is optional.
+func Parse(line string) (*Command, error) {
+	args, err := split(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+	cmd := &Command{Headers: make(http.Header)}
+	var data []string
+	head := false
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		name, value, hasValue := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			name, value, hasValue = strings.Cut(arg, "=")
+		} else if strings.HasPrefix(arg, "-") && len(arg) > 2 {
+			if expanded, ok := expandSwitches(arg); ok {
+				args = slices.Insert(args, i+1, expanded...)
+				continue
+			}
+			// attached short value, e.g. -XPOST
+			name, value, hasValue = arg[:2], arg[2:], true
+		}
+		next := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("missing value for %s", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+			if cmd.URL != "" {
+				return nil, fmt.Errorf("unexpected argument '%s'", arg)
+			}
+			cmd.URL = arg
+		case name == "-X" || name == "--request":
+			if cmd.Method, err = next(); err != nil {
+				return nil, err
+			}
+			cmd.Method = strings.ToUpper(cmd.Method)
+		case name == "-H" || name == "--header":
+			header, err := next()
+			if err != nil {
+				return nil, err
+			}
+			key, val, ok := strings.Cut(header, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header '%s'", header)
+			}
+			cmd.Headers.Add(strings.TrimSpace(key), strings.TrimSpace(val))
+		case name == "-d" || name == "--data" || name == "--data-raw" || name == "--data-binary" || name == "--data-ascii":
+			d, err := next()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, d)
+		case name == "--data-urlencode":
+			d, err := next()
+			if err != nil {
+				return nil, err
+			}
+			key, val, ok := strings.Cut(d, "=")
+			if ok {
+				d = key + "=" + url.QueryEscape(val)
+			} else {
+				d = url.QueryEscape(d)
+			}
+			data = append(data, d)
+		case name == "--json":
+			d, err := next()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, d)
+			setDefault(cmd.Headers, "Content-Type", "application/json")
+			setDefault(cmd.Headers, "Accept", "application/json")
+		case name == "-u" || name == "--user":
+			user, err := next()
+			if err != nil {
+				return nil, err
+			}
+			cmd.Username, cmd.Password, _ = strings.Cut(user, ":")
+		case name == "-A" || name == "--user-agent":
+			agent, err := next()
+			if err != nil {
+				return nil, err
+			}
+			cmd.Headers.Set("User-Agent", agent)
+		case name == "--url":
+			if cmd.URL, err = next(); err != nil {
+				return nil, err
+			}
+		case name == "-I" || name == "--head":
+			head = true
+		case slices.Contains(switches, name):
+		case slices.Contains(ignored, name):
+			if _, err := next(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported curl option '%s'", name)
+		}
+	}
+	if cmd.URL == "" {
+		return nil, errors.New("no URL found in curl command")
+	}
+	if len(data) > 0 {
+		cmd.Body = strings.Join(data, "&")
+		setDefault(cmd.Headers, "Content-Type", "application/x-www-form-urlencoded")
+	}
+	if cmd.Method == "" {
+		switch {
+		case head:
+			cmd.Method = http.MethodHead
+		case len(data) > 0:
+			cmd.Method = http.MethodPost
+		default:
+			cmd.Method = http.MethodGet
+		}
+	}
+	return cmd, nil
+}
+
+// expandSwitches splits combined switches such as -sSL
+func expandSwitches(arg string) ([]string, bool) {
+	var expanded []string
+	for _, c := range arg[1:] {
+		flag := "-" + string(c)
+		if !slices.Contains(switches, flag) {
+			return nil, false
+		}
+		expanded = append(expanded, flag)
+	}
+	return expanded, true
+}
+
+func setDefault(h http.Header, key, value string) {
+	if h.Get(key) == "" {
+		h.Set(key, value)
+	}
+}
+
+// split breaks a command line into arguments, honouring shell quoting and line continuations
+func split(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			if line[i] == '\n' || (line[i] == '\r' && i+1 < len(line) && line[i+1] == '\n') {
+				if line[i] == '\r' {
+					i++
+				}
+				continue
+			}
+			current.WriteByte(line[i])
+			inArg = true
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end == -1 {
+				return nil, errors.New("unterminated single quote")
+			}
+			current.WriteString(line[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case c == '"':
+			i++
+			for ; i < len(line) && line[i] != '"'; i++ {
+				if line[i] == '\\' && i+1 < len(line) && strings.IndexByte("\"\\$`", line[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(line[i])
+			}
+			if i >= len(line) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// Render formats req as a curl command. Basic credentials are written with -u.
+func Render(req *http.Request) (string, error) {
+	parts := []string{"curl"}
+	if req.Method != http.MethodGet {
+		parts = append(parts, "-X", req.Method)
+	}
+	parts = append(parts, Quote(req.URL.String()))
+	user, pass, basic := req.BasicAuth()
+	if basic {
+		parts = append(parts, "-u", Quote(user+":"+pass))
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if basic && name == "Authorization" {
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			if name == "Authorization" && value == "Bearer "+auth.TokenVariable {
+				// double quotes so the shell expands the variable
+				parts = append(parts, "-H", `"`+name+": "+value+`"`)
+				continue
+			}
+			parts = append(parts, "-H", Quote(name+": "+value))
+		}
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return "", err
+		}
+		if len(body) > 0 {
+			parts = append(parts, "--data-raw", Quote(string(body)))
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// Quote quotes s for a POSIX shell when needed
+func Quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
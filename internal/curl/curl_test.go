@@ -0,0 +1,115 @@
+package curl
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	// Arrange
+	line := `curl -sS -X post 'https://api.example.com/v1/users?x=1' \
+  -H "Content-Type: application/json" \
+  -H 'X-Note: it'\''s' \
+  -u admin:s3cret \
+  --data-raw '{"name": "a b"}'`
+
+	// Act
+	cmd, err := Parse(line)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if cmd.Method != "POST" || cmd.URL != "https://api.example.com/v1/users?x=1" {
+		t.Errorf("unexpected request line: %s %s", cmd.Method, cmd.URL)
+	}
+	if cmd.Headers.Get("Content-Type") != "application/json" || cmd.Headers.Get("X-Note") != "it's" {
+		t.Errorf("unexpected headers: %v", cmd.Headers)
+	}
+	if cmd.Username != "admin" || cmd.Password != "s3cret" {
+		t.Errorf("unexpected credentials: %s:%s", cmd.Username, cmd.Password)
+	}
+	if cmd.Body != `{"name": "a b"}` {
+		t.Errorf("unexpected body: %s", cmd.Body)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	tests := map[string]string{
+		"curl https://x.test/a":                     "GET",
+		"curl -d a=1 -d b=2 https://x.test/a":       "POST",
+		"curl -XDELETE https://x.test/a":            "DELETE",
+		"curl --request=PUT --url https://x.test/a": "PUT",
+	}
+	for line, want := range tests {
+		cmd, err := Parse(line)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", line, err)
+			continue
+		}
+		if cmd.Method != want {
+			t.Errorf("%s: expected %s, got %s", line, want, cmd.Method)
+		}
+	}
+
+	cmd, err := Parse("curl -d a=1 -d b=2 https://x.test/a")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if cmd.Body != "a=1&b=2" || cmd.Headers.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Errorf("unexpected form body: %s %v", cmd.Body, cmd.Headers)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, line := range []string{"curl -H", "curl 'https://x.test", "curl -X GET", "curl --proxy p https://x.test"} {
+		if _, err := Parse(line); err == nil {
+			t.Errorf("%s: expected error, got nil", line)
+		}
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	// Arrange
+	req, err := http.NewRequest("PATCH", "https://api.example.com/users/1", strings.NewReader(`{"note": "it's"}`))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth("admin", "s3cret")
+
+	// Act
+	line, err := Render(req)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	cmd, err := Parse(line)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("failed to parse rendered command %s: %v", line, err)
+	}
+	if cmd.Method != "PATCH" || cmd.URL != "https://api.example.com/users/1" || cmd.Body != `{"note": "it's"}` {
+		t.Errorf("round trip changed request: %+v", cmd)
+	}
+	if cmd.Username != "admin" || cmd.Password != "s3cret" || cmd.Headers.Get("Authorization") != "" {
+		t.Errorf("expected credentials as -u, got %+v", cmd)
+	}
+}
+
+func TestRenderTokenVariable(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://api.example.com/me", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer $TOKEN")
+
+	line, err := Render(req)
+	if err != nil {
+		t.Fatalf("Render returned error: %v", err)
+	}
+	if want := `curl https://api.example.com/me -H "Authorization: Bearer $TOKEN"`; line != want {
+		t.Errorf("expected %s, got %s", want, line)
+	}
+}
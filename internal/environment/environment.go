@@ -0,0 +1,19 @@
+// Package environment handles per-project environments and their variables
+package environment
+
+import "time"
+
+type Environment struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	ProjectID   uint              `gorm:"uniqueIndex:idx_project_env_name" json:"project_id"`
+	Name        string            `gorm:"uniqueIndex:idx_project_env_name" json:"name"`
+	BaseURL     string            `gorm:"column:base_url" json:"base_url"` // overrides the project's base URL when set
+	Variables   map[string]string `gorm:"serializer:json" json:"variables"`
+	Active      bool              `json:"active"` // used by goapi test when --env is not given
+	DateCreated time.Time         `gorm:"autoCreateTime" json:"date_created"`
+}
+
+type UpdateEnvironmentInput struct {
+	BaseURL   *string            `gorm:"column:base_url" json:"base_url,omitempty"`
+	Variables *map[string]string `gorm:"serializer:json" json:"variables,omitempty"`
+}
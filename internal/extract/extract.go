@@ -0,0 +1,109 @@
+// Package extract captures values from API responses into variables for later requests
+package extract
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+)
+
+type Source string
+
+const (
+	Body   Source = "body"   // JSON path into the response body
+	Header Source = "header" // response header value
+	Regex  Source = "regex"  // first capture group (or whole match) of a regex on the body
+)
+
+// Extraction stores part of a response in a named variable
+type Extraction struct {
+	Name   string `json:"name"`
+	Source Source `json:"source"`
+	Expr   string `json:"expr"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Parse parses "name=$.json.path", "name=header:Name" or "name=regex:pattern".
+// "body:" may prefix a JSON path explicitly.
+func Parse(spec string) (Extraction, error) {
+	name, expr, ok := strings.Cut(spec, "=")
+	name = strings.TrimSpace(name)
+	if !ok || !namePattern.MatchString(name) {
+		return Extraction{}, fmt.Errorf("invalid extraction '%s': expected name=$.path, name=header:Name or name=regex:pattern", spec)
+	}
+	e := Extraction{Name: name, Source: Body, Expr: strings.TrimSpace(expr)}
+	if source, rest, ok := strings.Cut(e.Expr, ":"); ok {
+		switch Source(source) {
+		case Body, Header, Regex:
+			e.Source, e.Expr = Source(source), rest
+		}
+	}
+	return e, e.Validate()
+}
+
+// Validate checks that the expression can be evaluated
+func (e Extraction) Validate() error {
+	if e.Expr == "" {
+		return fmt.Errorf("extraction '%s' has no expression", e.Name)
+	}
+	switch e.Source {
+	case Body:
+		if _, err := jsonpath.Parse(e.Expr); err != nil {
+			return fmt.Errorf("extraction '%s': %w", e.Name, err)
+		}
+	case Header:
+	case Regex:
+		re, err := regexp.Compile(e.Expr)
+		if err != nil {
+			return fmt.Errorf("extraction '%s': invalid regex: %w", e.Name, err)
+		}
+		if re.NumSubexp() > 1 {
+			return fmt.Errorf("extraction '%s': regex must have at most one capture group", e.Name)
+		}
+	default:
+		return fmt.Errorf("extraction '%s': invalid source: %s", e.Name, e.Source)
+	}
+	return nil
+}
+
+// String formats the extraction the way Parse reads it
+func (e Extraction) String() string {
+	if e.Source == Body {
+		return e.Name + "=" + e.Expr
+	}
+	return e.Name + "=" + string(e.Source) + ":" + e.Expr
+}
+
+// Apply evaluates the extraction against resp
+func (e Extraction) Apply(resp *api.Response) (string, error) {
+	switch e.Source {
+	case Body:
+		value, err := jsonpath.LookupBytes(resp.Body, e.Expr)
+		if err != nil {
+			return "", err
+		}
+		return jsonpath.String(value), nil
+	case Header:
+		values := resp.Headers.Values(e.Expr)
+		if len(values) == 0 {
+			return "", fmt.Errorf("header %s not present", e.Expr)
+		}
+		return values[0], nil
+	case Regex:
+		re, err := regexp.Compile(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindSubmatch(resp.Body)
+		if match == nil {
+			return "", fmt.Errorf("regex %s did not match", e.Expr)
+		}
+		return string(match[len(match)-1]), nil
+	default:
+		return "", fmt.Errorf("invalid source: %s", e.Source)
+	}
+}
@@ -0,0 +1,77 @@
+package extract
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/api"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]Extraction{
+		"id=$.data.id":             {Name: "id", Source: Body, Expr: "$.data.id"},
+		"id=body:data.id":          {Name: "id", Source: Body, Expr: "data.id"},
+		"loc=header:Location":      {Name: "loc", Source: Header, Expr: "Location"},
+		`csrf=regex:token="(\w+)"`: {Name: "csrf", Source: Regex, Expr: `token="(\w+)"`},
+	}
+	for spec, want := range tests {
+		got, err := Parse(spec)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", spec, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", spec, want, got)
+		}
+		if again, _ := Parse(got.String()); again != got {
+			t.Errorf("%s: String() does not round trip: %s", spec, got.String())
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"$.id", "bad name=$.id", "id=", "id=$.a[", "id=regex:(a)(b)", "id=regex:("} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%s: expected error, got nil", spec)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	// Arrange
+	resp := &api.Response{
+		Headers: http.Header{"Location": {"/users/12345678901234567890"}},
+		Body:    []byte(`{"id": 12345678901234567890, "name": "Ada", "html": "<input value=\"abc\">"}`),
+	}
+	tests := map[string]string{
+		"id=$.id":                   "12345678901234567890",
+		"name=$.name":               "Ada",
+		"loc=header:location":       "/users/12345678901234567890",
+		`v=regex:value=\\"(\w+)\\"`: "abc",
+		`whole=regex:Ad.`:           "Ada",
+	}
+
+	for spec, want := range tests {
+		e, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("%s: failed to parse: %v", spec, err)
+		}
+
+		// Act
+		got, err := e.Apply(resp)
+
+		// Assert
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", spec, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %s, got %s", spec, want, got)
+		}
+	}
+
+	missing, _ := Parse("x=header:X-Missing")
+	if _, err := missing.Apply(resp); err == nil {
+		t.Errorf("expected error for missing header, got nil")
+	}
+}
@@ -0,0 +1,196 @@
+// Package har converts HTTP Archive (HAR) captures into goapi routes
+package har
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+type Archive struct {
+	Log struct {
+		Entries []Entry `json:"entries"`
+	} `json:"log"`
+}
+
+type Entry struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method   string    `json:"method"`
+	URL      string    `json:"url"`
+	Headers  []Header  `json:"headers"`
+	PostData *PostData `json:"postData,omitempty"`
+}
+
+type Response struct {
+	Status  int      `json:"status"`
+	Headers []Header `json:"headers"`
+	Content struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+		Encoding string `json:"encoding,omitempty"`
+	} `json:"content"`
+}
+
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Load reads a HAR file
+func Load(path string) (*Archive, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var a Archive
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("invalid HAR file: %w", err)
+	}
+	return &a, nil
+}
+
+// Options controls how entries are converted
+type Options struct {
+	Host    string // only convert entries for this host; default is the host of the first entry
+	BaseURL string // prefix stripped from entry URLs; default is the scheme and host
+	Expect  bool   // record captured responses as expectations
+}
+
+// Import is the result of converting an archive
+type Import struct {
+	BaseURL    string
+	Routes     []*route.Route
+	Duplicates int // entries with a method and path already seen
+	OtherHosts int // entries skipped because of their host
+}
+
+// Convert turns entries into routes, keeping the first entry for each method and path.
+// Routes are named "METHOD /path".
+func (a *Archive) Convert(opts Options) (*Import, error) {
+	result := &Import{BaseURL: strings.TrimSuffix(opts.BaseURL, "/")}
+	seen := make(map[string]bool)
+	for _, entry := range a.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		if opts.Host == "" {
+			opts.Host = u.Host
+		}
+		if !strings.EqualFold(u.Host, opts.Host) && !strings.EqualFold(u.Hostname(), opts.Host) {
+			result.OtherHosts++
+			continue
+		}
+		if result.BaseURL == "" {
+			result.BaseURL = u.Scheme + "://" + u.Host
+		}
+		path, ok := strings.CutPrefix(u.Scheme+"://"+u.Host+u.EscapedPath(), result.BaseURL)
+		if !ok {
+			result.OtherHosts++
+			continue
+		}
+		if path == "" {
+			path = "/"
+		}
+		method, err := route.ParseHTTPMethod(entry.Request.Method)
+		if err != nil {
+			continue
+		}
+		key := string(method) + " " + path
+		if seen[key] {
+			result.Duplicates++
+			continue
+		}
+		seen[key] = true
+		if u.RawQuery != "" {
+			path += "?" + u.RawQuery
+		}
+		result.Routes = append(result.Routes, convertEntry(key, method, path, entry, opts.Expect))
+	}
+	if len(result.Routes) == 0 {
+		return nil, fmt.Errorf("no requests found for host '%s'", opts.Host)
+	}
+	return result, nil
+}
+
+func convertEntry(name string, method route.HTTPMethod, path string, entry Entry, expect bool) *route.Route {
+	r := &route.Route{
+		Name:    name,
+		Method:  method,
+		Path:    path,
+		Headers: make(route.Headers),
+	}
+	for _, h := range entry.Request.Headers {
+		r.SetCapturedHeader(h.Name, h.Value)
+	}
+	if entry.Request.PostData != nil {
+		r.Body = entry.Request.PostData.Text
+		if r.ContentType == "" {
+			r.ContentType = entry.Request.PostData.MimeType
+		}
+	}
+	if entry.Response.Status > 0 {
+		r.Example = Example(entry.Response)
+		if expect {
+			r.Expect = Baseline(entry.Response)
+		}
+	}
+	return r
+}
+
+// Example turns a captured response into a route's mock response. Binary bodies,
+// which HAR files store base64 encoded, are left out.
+func Example(resp Response) route.Example {
+	e := route.Example{Status: resp.Status}
+	if resp.Content.MimeType != "" {
+		e.Headers = map[string]string{"Content-Type": resp.Content.MimeType}
+	}
+	if resp.Content.Encoding == "" {
+		e.Body = resp.Content.Text
+	}
+	return e
+}
+
+// Baseline builds expectations from a captured response: its status, its media type
+// and the presence of the top-level fields of a JSON object body
+func Baseline(resp Response) assert.Expectation {
+	e := assert.Expectation{Status: strconv.Itoa(resp.Status)}
+	if mediaType, _, err := mime.ParseMediaType(resp.Content.MimeType); err == nil && mediaType != "" {
+		e.Headers = map[string]string{"Content-Type": "^" + regexp.QuoteMeta(mediaType)}
+	}
+	if resp.Content.Encoding != "" {
+		return e
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(resp.Content.Text), &body); err != nil {
+		return e
+	}
+	keys := make([]string, 0, len(body))
+	for key := range body {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		e.Body = append(e.Body, assert.BodyCheck{Path: jsonpath.Child("$", key), Op: assert.Exists})
+	}
+	return e
+}
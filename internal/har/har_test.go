@@ -0,0 +1,107 @@
+package har
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+const archiveJSON = `{"log": {"entries": [
+  {
+    "request": {
+      "method": "GET",
+      "url": "https://api.example.com/v1/users?page=1",
+      "headers": [
+        {"name": ":authority", "value": "api.example.com"},
+        {"name": "accept-encoding", "value": "gzip"},
+        {"name": "authorization", "value": "Bearer x"}
+      ]
+    },
+    "response": {
+      "status": 200,
+      "content": {"mimeType": "application/json; charset=utf-8", "text": "{\"items\": [], \"next.page\": 2}"}
+    }
+  },
+  {
+    "request": {"method": "GET", "url": "https://api.example.com/v1/users?page=2", "headers": []},
+    "response": {"status": 200, "content": {}}
+  },
+  {
+    "request": {
+      "method": "POST",
+      "url": "https://api.example.com/v1/users",
+      "headers": [{"name": "Content-Type", "value": "application/json"}],
+      "postData": {"mimeType": "application/json", "text": "{\"name\": \"a\"}"}
+    },
+    "response": {"status": 201, "content": {"mimeType": "text/plain", "text": "ok"}}
+  },
+  {
+    "request": {"method": "GET", "url": "https://cdn.example.com/app.js", "headers": []},
+    "response": {"status": 200, "content": {}}
+  }
+]}}`
+
+func TestConvert(t *testing.T) {
+	// Arrange
+	var a Archive
+	if err := json.Unmarshal([]byte(archiveJSON), &a); err != nil {
+		t.Fatalf("failed to parse archive: %v", err)
+	}
+
+	// Act
+	imported, err := a.Convert(Options{Host: "api.example.com", BaseURL: "https://api.example.com/v1", Expect: true})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	if imported.BaseURL != "https://api.example.com/v1" || imported.Duplicates != 1 || imported.OtherHosts != 1 {
+		t.Errorf("unexpected import: %+v", imported)
+	}
+	if len(imported.Routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(imported.Routes))
+	}
+	list := imported.Routes[0]
+	if list.Name != "GET /users" || list.Path != "/users?page=1" {
+		t.Errorf("unexpected route: %s %s", list.Name, list.Path)
+	}
+	if len(list.Headers) != 1 || list.Headers["Authorization"] != "Bearer x" {
+		t.Errorf("expected only the authorization header, got %v", list.Headers)
+	}
+	if list.Expect.Status != "200" || list.Expect.Headers["Content-Type"] != "^application/json" {
+		t.Errorf("unexpected expectation: %+v", list.Expect)
+	}
+	if len(list.Expect.Body) != 2 || list.Expect.Body[1].Path != "$['next.page']" {
+		t.Errorf("unexpected body checks: %+v", list.Expect.Body)
+	}
+	create := imported.Routes[1]
+	if create.Method != route.POST || create.Body != `{"name": "a"}` || create.ContentType != "application/json" {
+		t.Errorf("unexpected route: %+v", create)
+	}
+	if create.Expect.Status != "201" || len(create.Expect.Body) != 0 {
+		t.Errorf("unexpected expectation: %+v", create.Expect)
+	}
+}
+
+func TestConvertDefaultsToFirstHost(t *testing.T) {
+	// Arrange
+	var a Archive
+	if err := json.Unmarshal([]byte(archiveJSON), &a); err != nil {
+		t.Fatalf("failed to parse archive: %v", err)
+	}
+
+	// Act
+	imported, err := a.Convert(Options{})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	if imported.BaseURL != "https://api.example.com" || imported.Routes[0].Path != "/v1/users?page=1" {
+		t.Errorf("unexpected import: %s %s", imported.BaseURL, imported.Routes[0].Path)
+	}
+	if !imported.Routes[0].Expect.IsZero() {
+		t.Errorf("expected no expectations without Expect, got %+v", imported.Routes[0].Expect)
+	}
+}
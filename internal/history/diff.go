@@ -0,0 +1,106 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Change compares one route across two runs
+type Change struct {
+	RouteName string
+	From      *Result // nil if the route was not in the earlier run
+	To        *Result // nil if the route was not in the later run
+	Notes     []string
+	Slower    bool // latency grew by more than the threshold
+}
+
+// Regression reports whether the change is worse in the later run: it started
+// failing, left the 2xx range or got slower
+func (c Change) Regression() bool {
+	if c.From == nil || c.To == nil {
+		return false
+	}
+	if c.From.Passed && !c.To.Passed {
+		return true
+	}
+	if success(c.From.StatusCode) && !success(c.To.StatusCode) {
+		return true
+	}
+	return c.Slower
+}
+
+func success(status int) bool {
+	return status >= 200 && status < 300
+}
+
+// DurationDelta is the change in latency, zero if the route is missing from either run
+func (c Change) DurationDelta() time.Duration {
+	if c.From == nil || c.To == nil {
+		return 0
+	}
+	return c.To.Duration - c.From.Duration
+}
+
+// Diff compares two runs route by route, or step by step for suite runs. Routes whose latency grew by more than
+// threshold percent are reported as slower.
+func Diff(from, to *Run, threshold float64) []Change {
+	key := func(r *Result) string {
+		if r.Step != 0 {
+			return fmt.Sprintf("step:%d", r.Step)
+		}
+		if r.RouteID != 0 {
+			return fmt.Sprintf("id:%d", r.RouteID)
+		}
+		return "name:" + r.RouteName
+	}
+	earlier := make(map[string]*Result, len(from.Results))
+	for i := range from.Results {
+		earlier[key(&from.Results[i])] = &from.Results[i]
+	}
+
+	var changes []Change
+	seen := make(map[string]bool)
+	for i := range to.Results {
+		b := &to.Results[i]
+		k := key(b)
+		seen[k] = true
+		a := earlier[k]
+		c := Change{RouteName: b.RouteName, From: a, To: b}
+		if a == nil {
+			c.Notes = append(c.Notes, "new")
+		} else {
+			c.Notes, c.Slower = compare(a, b, threshold)
+		}
+		changes = append(changes, c)
+	}
+	for i := range from.Results {
+		a := &from.Results[i]
+		if !seen[key(a)] {
+			changes = append(changes, Change{RouteName: a.RouteName, From: a, Notes: []string{"removed"}})
+		}
+	}
+	return changes
+}
+
+func compare(a, b *Result, threshold float64) (notes []string, slower bool) {
+	switch {
+	case a.Passed && !b.Passed:
+		notes = append(notes, "now failing")
+	case !a.Passed && b.Passed:
+		notes = append(notes, "fixed")
+	}
+	if a.StatusCode != b.StatusCode {
+		notes = append(notes, fmt.Sprintf("status %d -> %d", a.StatusCode, b.StatusCode))
+	}
+	if a.Duration > 0 && b.Duration > 0 {
+		pct := float64(b.Duration-a.Duration) / float64(a.Duration) * 100
+		switch {
+		case pct > threshold:
+			notes = append(notes, fmt.Sprintf("slower +%.0f%%", pct))
+			slower = true
+		case pct < -threshold:
+			notes = append(notes, fmt.Sprintf("faster %.0f%%", pct))
+		}
+	}
+	return notes, slower
+}
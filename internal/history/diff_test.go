@@ -0,0 +1,77 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	// Arrange: a route that regressed, one that was removed and one that was added
+	from := &Run{Results: []Result{
+		{RouteID: 1, RouteName: "List", StatusCode: 200, Duration: 100 * time.Millisecond, Passed: true},
+		{RouteID: 2, RouteName: "Get", StatusCode: 200, Duration: 100 * time.Millisecond, Passed: true},
+		{RouteID: 3, RouteName: "Old", StatusCode: 200, Duration: 100 * time.Millisecond, Passed: true},
+	}}
+	to := &Run{Results: []Result{
+		{RouteID: 1, RouteName: "List", StatusCode: 200, Duration: 105 * time.Millisecond, Passed: true},
+		{RouteID: 2, RouteName: "Get", StatusCode: 500, Duration: 300 * time.Millisecond, Passed: false},
+		{RouteID: 4, RouteName: "New", StatusCode: 201, Duration: 50 * time.Millisecond, Passed: true},
+	}}
+
+	// Act
+	changes := Diff(from, to, 20)
+
+	// Assert
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %d", len(changes))
+	}
+	if len(changes[0].Notes) != 0 || changes[0].Regression() {
+		t.Errorf("expected List to be unchanged within threshold, got %v", changes[0].Notes)
+	}
+	if !changes[1].Regression() || len(changes[1].Notes) != 3 {
+		t.Errorf("expected Get to regress in result, status and latency, got %v", changes[1].Notes)
+	}
+	if changes[2].RouteName != "New" || changes[2].Notes[0] != "new" {
+		t.Errorf("expected New to be reported as new, got %+v", changes[2])
+	}
+	if changes[3].RouteName != "Old" || changes[3].Notes[0] != "removed" {
+		t.Errorf("expected Old to be reported as removed, got %+v", changes[3])
+	}
+}
+
+func TestDiffFixedIsNotRegression(t *testing.T) {
+	from := &Run{Results: []Result{{RouteID: 1, RouteName: "Get", StatusCode: 500, Duration: 100 * time.Millisecond}}}
+	to := &Run{Results: []Result{{RouteID: 1, RouteName: "Get", StatusCode: 200, Duration: 100 * time.Millisecond, Passed: true}}}
+
+	changes := Diff(from, to, 20)
+
+	if len(changes) != 1 || len(changes[0].Notes) != 2 {
+		t.Fatalf("expected fixed and status notes, got %+v", changes)
+	}
+	if changes[0].Regression() {
+		t.Errorf("expected a fixed route not to be a regression, got %v", changes[0].Notes)
+	}
+}
+
+func TestDiffSuiteSteps(t *testing.T) {
+	// a suite that logs in twice uses the same route in two steps
+	from := &Run{Suite: "checkout", Results: []Result{
+		{RouteID: 1, Step: 1, RouteName: "Login", StatusCode: 200, Passed: true},
+		{RouteID: 2, Step: 2, RouteName: "Buy", StatusCode: 201, Passed: true},
+		{RouteID: 1, Step: 3, RouteName: "Login", StatusCode: 200, Passed: true},
+	}}
+	to := &Run{Suite: "checkout", Results: []Result{
+		{RouteID: 1, Step: 1, RouteName: "Login", StatusCode: 200, Passed: true},
+		{RouteID: 2, Step: 2, RouteName: "Buy", StatusCode: 201, Passed: true},
+		{RouteID: 1, Step: 3, RouteName: "Login", StatusCode: 401},
+	}}
+
+	changes := Diff(from, to, 20)
+
+	if len(changes) != 3 {
+		t.Fatalf("expected one change per step, got %d", len(changes))
+	}
+	if changes[0].Regression() || !changes[2].Regression() {
+		t.Errorf("expected only the second login to regress, got %+v", changes)
+	}
+}
@@ -0,0 +1,59 @@
+// Package history records past test runs and compares them
+package history
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/text"
+)
+
+// MaxBodyBytes is how much of each response body is kept in a recorded result
+const MaxBodyBytes = 4096
+
+// Run is a single execution of goapi test
+type Run struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	ProjectID   uint          `gorm:"index" json:"project_id"`
+	Environment string        `json:"environment,omitempty"`
+	Suite       string        `json:"suite,omitempty"` // suite that ran, empty for goapi test
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"duration_ns"`
+	Passed      int           `json:"passed"`
+	Failed      int           `json:"failed"`
+	Results     []Result      `gorm:"foreignKey:RunID" json:"results"`
+}
+
+func (Run) TableName() string {
+	return "history_runs"
+}
+
+// Result is the recorded outcome of one route in a run
+type Result struct {
+	ID           uint          `gorm:"primaryKey" json:"id"`
+	RunID        uint          `gorm:"index" json:"run_id"`
+	RouteID      uint          `json:"route_id"`
+	Step         int           `json:"step,omitempty"` // position in a suite run, which may use a route more than once
+	RouteName    string        `json:"route_name"`
+	Method       string        `json:"method"`
+	Path         string        `json:"path"`
+	StatusCode   int           `json:"status_code"`
+	Duration     time.Duration `json:"duration_ns"`
+	Passed       bool          `json:"passed"`
+	Error        string        `json:"error,omitempty"`
+	Failures     []string      `gorm:"serializer:json" json:"failures,omitempty"` // request error and failed assertions
+	ResponseBody string        `json:"response_body,omitempty"`                   // truncated to MaxBodyBytes
+}
+
+func (Result) TableName() string {
+	return "history_results"
+}
+
+// Truncate shortens body to at most MaxBodyBytes, without splitting a UTF-8 character
+func Truncate(body string) string {
+	if len(body) <= MaxBodyBytes {
+		return body
+	}
+	kept := text.Cut(body, MaxBodyBytes)
+	return kept + fmt.Sprintf("... (%d bytes truncated)", len(body)-len(kept))
+}
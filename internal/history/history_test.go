@@ -0,0 +1,27 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateKeepsRunes(t *testing.T) {
+	// "é" is two bytes, so the limit falls in the middle of one
+	body := "a" + strings.Repeat("é", MaxBodyBytes)
+
+	got := Truncate(body)
+
+	if !utf8.ValidString(got) {
+		t.Fatal("expected valid UTF-8 after truncation")
+	}
+	if !strings.HasPrefix(got, "a"+strings.Repeat("é", MaxBodyBytes/2-1)+"...") {
+		t.Errorf("expected truncation at the last whole character, got %q", got[len(got)-40:])
+	}
+	if !strings.HasSuffix(got, "(4098 bytes truncated)") {
+		t.Errorf("expected truncated byte count, got %q", got[len(got)-40:])
+	}
+	if short := "héllo"; Truncate(short) != short {
+		t.Errorf("expected short body unchanged, got %q", Truncate(short))
+	}
+}
@@ -0,0 +1,166 @@
+// Package jsonpath resolves simple JSONPath expressions such as $.data.items[0].id
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is returned when a path does not exist in a document
+var ErrNotFound = errors.New("path not found")
+
+// Segment is one step of a path: an object key, an array index, or a wildcard
+// matching every member of an object or array
+type Segment struct {
+	Key      string
+	Index    int
+	IsIndex  bool
+	Wildcard bool
+}
+
+// Parse splits a path like $.users[0]['first name'] into segments.
+// The leading '$' is optional, and [*] or .* match every member.
+func Parse(path string) ([]Segment, error) {
+	rest := strings.TrimSpace(path)
+	rest = strings.TrimPrefix(rest, "$")
+	var segments []Segment
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %s: empty key", path)
+			}
+			if rest[:end] == "*" {
+				segments = append(segments, Segment{Wildcard: true})
+			} else {
+				segments = append(segments, Segment{Key: rest[:end]})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path %s: unclosed '['", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if inner == "*" {
+				segments = append(segments, Segment{Wildcard: true})
+				continue
+			}
+			if quoted, ok := unquote(inner); ok {
+				segments = append(segments, Segment{Key: quoted})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %s: bad index '%s'", path, inner)
+			}
+			segments = append(segments, Segment{Index: index, IsIndex: true})
+		default:
+			if len(segments) > 0 {
+				return nil, fmt.Errorf("invalid path %s: unexpected '%c'", path, rest[0])
+			}
+			// allow a bare leading key, e.g. data.token
+			rest = "." + rest
+		}
+	}
+	return segments, nil
+}
+
+// Lookup resolves path against a decoded JSON document
+func Lookup(doc any, path string) (any, error) {
+	segments, err := Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	for _, seg := range segments {
+		if seg.Wildcard {
+			return nil, fmt.Errorf("invalid path %s: wildcards match more than one value", path)
+		}
+		if seg.IsIndex {
+			arr, ok := current.([]any)
+			if !ok || seg.Index >= len(arr) {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+			}
+			current = arr[seg.Index]
+			continue
+		}
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+		current, ok = obj[seg.Key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, path)
+		}
+	}
+	return current, nil
+}
+
+// LookupBytes decodes body as JSON and resolves path against it. Numbers are
+// kept as json.Number so large IDs survive unchanged.
+func LookupBytes(body []byte, path string) (any, error) {
+	doc, err := Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	return Lookup(doc, path)
+}
+
+// Decode decodes a single JSON document, keeping numbers as json.Number
+func Decode(body []byte) (any, error) {
+	var doc any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("response body is not valid JSON: unexpected data after value")
+	}
+	return doc, nil
+}
+
+// String formats a resolved value for comparison: strings as-is, everything else as JSON
+func String(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// Child appends an object key to path, using bracket notation when the key
+// cannot be written after a dot
+func Child(path, key string) string {
+	if key != "" && !strings.ContainsAny(key, ".[]'\" ") {
+		return path + "." + key
+	}
+	return path + "['" + key + "']"
+}
+
+// Index appends an array index to path
+func Index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
@@ -0,0 +1,69 @@
+package jsonpath
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupBytes(t *testing.T) {
+	body := []byte(`{"data": {"users": [{"id": 7, "first name": "Ada"}]}, "token": "abc"}`)
+
+	tests := map[string]string{
+		"$.token":                       "abc",
+		"token":                         "abc",
+		"$.data.users[0].id":            "7",
+		"$.data.users[0]['first name']": "Ada",
+		"$.data.users[0]":               `{"first name":"Ada","id":7}`,
+	}
+	for path, want := range tests {
+		got, err := LookupBytes(body, path)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", path, err)
+			continue
+		}
+		if String(got) != want {
+			t.Errorf("%s: expected %s, got %s", path, want, String(got))
+		}
+	}
+}
+
+func TestLookupNotFound(t *testing.T) {
+	body := []byte(`{"items": [1, 2]}`)
+
+	for _, path := range []string{"$.missing", "$.items[5]", "$.items.key"} {
+		if _, err := LookupBytes(body, path); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound, got %v", path, err)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, path := range []string{"$.a[", "$.a[x]", "$..a"} {
+		if _, err := Parse(path); err == nil {
+			t.Errorf("%s: expected error, got nil", path)
+		}
+	}
+}
+
+func TestChild(t *testing.T) {
+	body := []byte(`{"a": {"b.c": 1, "d": 2}}`)
+
+	for _, path := range []string{Child(Child("$", "a"), "b.c"), Child(Child("$", "a"), "d")} {
+		if _, err := LookupBytes(body, path); err != nil {
+			t.Errorf("%s: expected no error, got %v", path, err)
+		}
+	}
+}
+
+func TestParseWildcard(t *testing.T) {
+	segments, err := Parse("$.items[*].*")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(segments) != 3 || !segments[1].Wildcard || !segments[2].Wildcard {
+		t.Errorf("expected wildcard segments, got %+v", segments)
+	}
+	if _, err := LookupBytes([]byte(`{"items": [1]}`), "$.items[*]"); err == nil {
+		t.Error("expected lookup of a wildcard path to fail")
+	}
+}
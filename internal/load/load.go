@@ -0,0 +1,272 @@
+// Package load sends a route repeatedly at a target rate and summarizes the latencies
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+)
+
+// Options controls the shape of a load test
+type Options struct {
+	RPS         float64       `json:"rps"`         // requests per second across all workers, 0 for no limit
+	Duration    time.Duration `json:"duration_ns"` // how long to keep sending requests
+	Concurrency int           `json:"concurrency"` // maximum requests in flight
+}
+
+// Sender sends one request
+type Sender func(ctx context.Context) (*api.Response, error)
+
+// Summary aggregates the outcome of a load test
+type Summary struct {
+	Requests        int            `json:"requests"`
+	Errors          int            `json:"errors"`
+	ErrorRate       float64        `json:"error_rate"`
+	Elapsed         time.Duration  `json:"elapsed_ns"`
+	RPS             float64        `json:"rps"` // achieved rate
+	StatusCodes     map[int]int    `json:"status_codes"`
+	TransportErrors map[string]int `json:"transport_errors,omitempty"`
+	Latency         Latency        `json:"latency"`
+	Histogram       []Bucket       `json:"histogram"`
+}
+
+// Latency holds latency statistics of requests that received a response
+type Latency struct {
+	Min  time.Duration `json:"min_ns"`
+	Mean time.Duration `json:"mean_ns"`
+	P50  time.Duration `json:"p50_ns"`
+	P90  time.Duration `json:"p90_ns"`
+	P99  time.Duration `json:"p99_ns"`
+	Max  time.Duration `json:"max_ns"`
+}
+
+// Bucket counts responses slower than the previous bound and at most UpperBound.
+// The last bucket has no upper bound and UpperBound 0.
+type Bucket struct {
+	UpperBound time.Duration `json:"le_ns"`
+	Count      int           `json:"count"`
+}
+
+// bucketBounds are the histogram bucket upper bounds
+var bucketBounds = []time.Duration{
+	time.Millisecond, 2 * time.Millisecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 20 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 200 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2 * time.Second, 5 * time.Second,
+}
+
+type sample struct {
+	status   int
+	duration time.Duration
+	err      error
+}
+
+// Run calls send from opts.Concurrency workers, paced to opts.RPS, until opts.Duration
+// has passed or ctx is cancelled. Requests in flight at the deadline are allowed to
+// finish. ok decides whether a status code counts as a success.
+func Run(ctx context.Context, send Sender, ok func(status int) bool, opts Options) *Summary {
+	deadline, stop := context.WithTimeout(ctx, opts.Duration)
+	defer stop()
+	var ticks <-chan time.Time
+	if opts.RPS > 0 {
+		// rates above one per nanosecond would round the interval down to zero
+		ticker := time.NewTicker(max(time.Duration(float64(time.Second)/opts.RPS), time.Nanosecond))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	var mu sync.Mutex
+	var samples []sample
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range max(1, opts.Concurrency) {
+		wg.Go(func() {
+			for {
+				if ticks != nil {
+					select {
+					case <-deadline.Done():
+						return
+					case <-ticks:
+					}
+				} else if deadline.Err() != nil {
+					return
+				}
+				resp, err := send(ctx)
+				if err != nil && ctx.Err() != nil {
+					return
+				}
+				s := sample{err: err}
+				if err == nil {
+					s.status, s.duration = resp.StatusCode, resp.Duration
+				}
+				mu.Lock()
+				samples = append(samples, s)
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	return summarize(samples, time.Since(start), ok)
+}
+
+func summarize(samples []sample, elapsed time.Duration, ok func(int) bool) *Summary {
+	s := &Summary{
+		Requests:    len(samples),
+		Elapsed:     elapsed,
+		StatusCodes: make(map[int]int),
+	}
+	if elapsed > 0 {
+		s.RPS = float64(len(samples)) / elapsed.Seconds()
+	}
+	var durations []time.Duration
+	for _, sample := range samples {
+		if sample.err != nil {
+			s.Errors++
+			if s.TransportErrors == nil {
+				s.TransportErrors = make(map[string]int)
+			}
+			s.TransportErrors[sample.err.Error()]++
+			continue
+		}
+		s.StatusCodes[sample.status]++
+		if !ok(sample.status) {
+			s.Errors++
+		}
+		durations = append(durations, sample.duration)
+	}
+	if s.Requests > 0 {
+		s.ErrorRate = float64(s.Errors) / float64(s.Requests)
+	}
+	s.Latency = latency(durations)
+	s.Histogram = histogram(durations)
+	return s
+}
+
+func latency(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	slices.Sort(durations)
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return Latency{
+		Min:  durations[0],
+		Mean: total / time.Duration(len(durations)),
+		P50:  Percentile(durations, 50),
+		P90:  Percentile(durations, 90),
+		P99:  Percentile(durations, 99),
+		Max:  durations[len(durations)-1],
+	}
+}
+
+// Percentile returns the nearest-rank percentile p of sorted durations
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+func histogram(durations []time.Duration) []Bucket {
+	buckets := make([]Bucket, len(bucketBounds)+1)
+	for i, bound := range bucketBounds {
+		buckets[i].UpperBound = bound
+	}
+	for _, d := range durations {
+		i, _ := slices.BinarySearch(bucketBounds, d)
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// Report is a summary together with what was tested, for trend tracking
+type Report struct {
+	Project   string    `json:"project"`
+	Route     string    `json:"route"`
+	Method    string    `json:"method"`
+	URL       string    `json:"url"`
+	StartedAt time.Time `json:"started_at"`
+	Target    Options   `json:"target"`
+	*Summary
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTable writes a human-readable summary with a latency histogram
+func (s *Summary) WriteTable(w io.Writer) error {
+	codes := make([]string, 0, len(s.StatusCodes))
+	for _, code := range slices.Sorted(maps.Keys(s.StatusCodes)) {
+		codes = append(codes, fmt.Sprintf("%d x%d", code, s.StatusCodes[code]))
+	}
+	l := s.Latency
+	rows := [][2]string{
+		{"Requests", fmt.Sprintf("%d (%.1f/s over %s)", s.Requests, s.RPS, s.Elapsed.Round(time.Millisecond))},
+		{"Errors", fmt.Sprintf("%d (%.2f%%)", s.Errors, s.ErrorRate*100)},
+		{"Status codes", strings.Join(codes, ", ")},
+	}
+	for _, msg := range slices.Sorted(maps.Keys(s.TransportErrors)) {
+		rows = append(rows, [2]string{"Transport error", fmt.Sprintf("%s x%d", msg, s.TransportErrors[msg])})
+	}
+	rows = append(rows, [2]string{"Latency", fmt.Sprintf("min %s  mean %s  p50 %s  p90 %s  p99 %s  max %s",
+		round(l.Min), round(l.Mean), round(l.P50), round(l.P90), round(l.P99), round(l.Max))})
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		if _, err := fmt.Fprintf(tw, "%s\t%s\n", row[0], row[1]); err != nil {
+			return err
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	peak := 0
+	for _, b := range s.Histogram {
+		peak = max(peak, b.Count)
+	}
+	if peak == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, b := range s.Histogram {
+		if b.Count == 0 {
+			continue
+		}
+		label := "> " + bucketBounds[len(bucketBounds)-1].String()
+		if b.UpperBound > 0 {
+			label = "<= " + b.UpperBound.String()
+		}
+		bar := strings.Repeat("#", max(1, b.Count*40/peak))
+		if _, err := fmt.Fprintf(tw, "%s\t%d\t%s\n", label, b.Count, bar); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	if d > time.Second {
+		return d.Round(time.Millisecond)
+	}
+	return d.Round(10 * time.Microsecond)
+}
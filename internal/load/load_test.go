@@ -0,0 +1,84 @@
+package load
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	tests := map[float64]time.Duration{50: 50 * time.Millisecond, 90: 90 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond, 0: time.Millisecond}
+	for p, want := range tests {
+		if got := Percentile(sorted, p); got != want {
+			t.Errorf("p%v: expected %v, got %v", p, want, got)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	// Arrange
+	samples := []sample{
+		{status: 200, duration: 3 * time.Millisecond},
+		{status: 200, duration: time.Millisecond},
+		{status: 500, duration: 10 * time.Second},
+		{err: errors.New("connection refused")},
+	}
+
+	// Act
+	s := summarize(samples, time.Second, func(status int) bool { return status < 400 })
+
+	// Assert
+	if s.Requests != 4 || s.Errors != 2 || s.ErrorRate != 0.5 || s.RPS != 4 {
+		t.Errorf("unexpected counts: %+v", s)
+	}
+	if s.StatusCodes[200] != 2 || s.StatusCodes[500] != 1 || s.TransportErrors["connection refused"] != 1 {
+		t.Errorf("unexpected distribution: %v %v", s.StatusCodes, s.TransportErrors)
+	}
+	if s.Latency.Min != time.Millisecond || s.Latency.P50 != 3*time.Millisecond || s.Latency.Max != 10*time.Second {
+		t.Errorf("unexpected latency: %+v", s.Latency)
+	}
+	if s.Histogram[0].Count != 1 || s.Histogram[2].Count != 1 || s.Histogram[len(s.Histogram)-1].Count != 1 {
+		t.Errorf("unexpected histogram: %+v", s.Histogram)
+	}
+}
+
+func TestRunPacesRequests(t *testing.T) {
+	// Arrange
+	var sent int32
+	send := func(ctx context.Context) (*api.Response, error) {
+		atomic.AddInt32(&sent, 1)
+		return &api.Response{StatusCode: 200, Duration: time.Millisecond}, nil
+	}
+
+	// Act
+	s := Run(context.Background(), send, func(int) bool { return true }, Options{RPS: 50, Duration: 500 * time.Millisecond, Concurrency: 4})
+
+	// Assert: 25 ticks fit in the window, allow for scheduling jitter
+	if s.Requests < 15 || s.Requests > 26 {
+		t.Errorf("expected about 25 requests at 50 rps, got %d", s.Requests)
+	}
+	if int(sent) != s.Requests {
+		t.Errorf("expected every request to be counted, sent %d, counted %d", sent, s.Requests)
+	}
+}
+
+func TestRunHugeRPS(t *testing.T) {
+	send := func(ctx context.Context) (*api.Response, error) {
+		return &api.Response{StatusCode: 200}, nil
+	}
+
+	// an interval under 1ns must not panic the ticker
+	s := Run(context.Background(), send, func(int) bool { return true }, Options{RPS: 5e9, Duration: 20 * time.Millisecond, Concurrency: 1})
+
+	if s.Requests == 0 {
+		t.Error("expected requests to be sent")
+	}
+}
@@ -0,0 +1,333 @@
+// Package manifest reads and writes goapi.yaml, a reviewable description of a
+// project, its environments and its routes
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/extract"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/schema"
+	"github.com/raworiginal/goapi/internal/snapshot"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the manifest file name used when none is given
+const DefaultFile = "goapi.yaml"
+
+// Manifest describes a project. Routes run in the order they are listed.
+type Manifest struct {
+	Project      Project       `yaml:"project"`
+	Environments []Environment `yaml:"environments,omitempty"`
+	Routes       []Route       `yaml:"routes,omitempty"`
+}
+
+type Project struct {
+	Name        string `yaml:"name"`
+	BaseURL     string `yaml:"base_url,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type Environment struct {
+	Name      string            `yaml:"name"`
+	BaseURL   string            `yaml:"base_url,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+	Active    bool              `yaml:"active,omitempty"`
+}
+
+// Route describes a route. Expectations, extractions and snapshot paths use the
+// same syntax as the route command flags.
+type Route struct {
+	Name           string            `yaml:"name"`
+	Method         string            `yaml:"method"`
+	Path           string            `yaml:"path"`
+	Description    string            `yaml:"description,omitempty"`
+	Params         map[string]string `yaml:"params,omitempty"` // default path parameter values
+	Headers        map[string]string `yaml:"headers,omitempty"`
+	ContentType    string            `yaml:"content_type,omitempty"`
+	Body           string            `yaml:"body,omitempty"`
+	Public         bool              `yaml:"public,omitempty"`
+	Expect         *Expect           `yaml:"expect,omitempty"`
+	Extract        []string          `yaml:"extract,omitempty"` // name=$.path, name=header:Name or name=regex:pattern
+	Example        *Example          `yaml:"example,omitempty"`
+	Schema         string            `yaml:"schema,omitempty"`
+	SnapshotIgnore []string          `yaml:"snapshot_ignore,omitempty"`
+}
+
+type Expect struct {
+	Status      string            `yaml:"status,omitempty"`
+	Headers     map[string]string `yaml:"headers,omitempty"`
+	JSON        []string          `yaml:"json,omitempty"` // $.path exists, $.path == value or $.path ~ regex
+	MaxDuration string            `yaml:"max_duration,omitempty"`
+}
+
+type Example struct {
+	Status  int               `yaml:"status,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+}
+
+// Load reads a manifest file, rejecting unknown keys so typos do not go unnoticed.
+// Schema files are resolved relative to the manifest and must exist.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(path)
+	for i, r := range m.Routes {
+		if r.Schema == "" {
+			continue
+		}
+		resolved, err := schema.Resolve(r.Schema, dir)
+		if err != nil {
+			return nil, fmt.Errorf("route '%s': %w", r.Name, err)
+		}
+		if _, err := schema.Load(resolved); err != nil {
+			return nil, fmt.Errorf("route '%s': invalid schema: %w", r.Name, err)
+		}
+		m.Routes[i].Schema = resolved
+	}
+	return m, nil
+}
+
+// RelativeTo rewrites schema file paths relative to dir, where the manifest is written
+func (m *Manifest) RelativeTo(dir string) {
+	for i, r := range m.Routes {
+		m.Routes[i].Schema = schema.Relative(r.Schema, dir)
+	}
+}
+
+// Parse reads a manifest and checks that it converts to valid models
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if _, _, _, err := m.Models(); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Write encodes the manifest as YAML
+func (m *Manifest) Write(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Models converts the manifest into the stored models, without IDs
+func (m *Manifest) Models() (*project.Project, []*environment.Environment, []*route.Route, error) {
+	if m.Project.Name == "" {
+		return nil, nil, nil, fmt.Errorf("project name cannot be empty")
+	}
+	p := &project.Project{Name: m.Project.Name, BaseURL: m.Project.BaseURL, Description: m.Project.Description}
+
+	var envs []*environment.Environment
+	active := 0
+	for _, e := range m.Environments {
+		if e.Name == "" {
+			return nil, nil, nil, fmt.Errorf("environment name cannot be empty")
+		}
+		if slices.ContainsFunc(envs, func(o *environment.Environment) bool { return o.Name == e.Name }) {
+			return nil, nil, nil, fmt.Errorf("environment '%s' is listed twice", e.Name)
+		}
+		if e.Active {
+			active++
+		}
+		envs = append(envs, &environment.Environment{Name: e.Name, BaseURL: e.BaseURL, Variables: nonEmpty(e.Variables), Active: e.Active})
+	}
+	if active > 1 {
+		return nil, nil, nil, fmt.Errorf("only one environment can be active")
+	}
+
+	var routes []*route.Route
+	for i, r := range m.Routes {
+		if r.Name == "" {
+			return nil, nil, nil, fmt.Errorf("route %d: name cannot be empty", i+1)
+		}
+		if slices.ContainsFunc(routes, func(o *route.Route) bool { return o.Name == r.Name }) {
+			return nil, nil, nil, fmt.Errorf("route '%s' is listed twice", r.Name)
+		}
+		converted, err := r.model()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("route '%s': %w", r.Name, err)
+		}
+		converted.Order = i + 1
+		routes = append(routes, converted)
+	}
+	return p, envs, routes, nil
+}
+
+func (r *Route) model() (*route.Route, error) {
+	method, err := route.ParseHTTPMethod(r.Method)
+	if err != nil {
+		return nil, err
+	}
+	if r.Path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+	params, err := route.BuildParams(r.Path, r.Params)
+	if err != nil {
+		return nil, err
+	}
+	converted := &route.Route{
+		Name:           r.Name,
+		Method:         method,
+		Path:           r.Path,
+		Params:         params,
+		Headers:        route.Headers{},
+		ContentType:    r.ContentType,
+		Body:           r.Body,
+		Public:         r.Public,
+		ResponseSchema: r.Schema,
+		SnapshotIgnore: r.SnapshotIgnore,
+		Description:    r.Description,
+	}
+	for name, value := range r.Headers {
+		converted.Headers[http.CanonicalHeaderKey(name)] = value
+	}
+	if r.Body != "" && r.ContentType == "" {
+		converted.ContentType = "application/json"
+	}
+	if r.Expect != nil {
+		if converted.Expect, err = r.Expect.model(); err != nil {
+			return nil, err
+		}
+	}
+	for _, spec := range r.Extract {
+		e, err := extract.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		converted.Extract = append(converted.Extract, e)
+	}
+	if r.Example != nil {
+		converted.Example = route.Example{Status: r.Example.Status, Headers: nonEmpty(r.Example.Headers), Body: r.Example.Body}
+	}
+	for _, path := range r.SnapshotIgnore {
+		if err := snapshot.ValidateIgnore(path); err != nil {
+			return nil, err
+		}
+	}
+	return converted, nil
+}
+
+func (e *Expect) model() (assert.Expectation, error) {
+	expect := assert.Expectation{Status: e.Status}
+	if e.Status != "" {
+		if _, _, err := assert.ParseStatus(e.Status); err != nil {
+			return expect, err
+		}
+	}
+	for name, pattern := range e.Headers {
+		if expect.Headers == nil {
+			expect.Headers = make(map[string]string)
+		}
+		expect.Headers[http.CanonicalHeaderKey(name)] = pattern
+	}
+	for _, expr := range e.JSON {
+		check, err := assert.ParseBodyCheck(expr)
+		if err != nil {
+			return expect, err
+		}
+		expect.Body = append(expect.Body, check)
+	}
+	if e.MaxDuration != "" {
+		d, err := time.ParseDuration(e.MaxDuration)
+		if err != nil {
+			return expect, fmt.Errorf("invalid max_duration: %w", err)
+		}
+		expect.MaxDuration = d
+	}
+	return expect, nil
+}
+
+// FromModels describes stored models as a manifest
+func FromModels(p *project.Project, envs []*environment.Environment, routes []*route.Route) *Manifest {
+	m := &Manifest{Project: Project{Name: p.Name, BaseURL: p.BaseURL, Description: p.Description}}
+	for _, e := range envs {
+		m.Environments = append(m.Environments, Environment{Name: e.Name, BaseURL: e.BaseURL, Variables: nonEmpty(e.Variables), Active: e.Active})
+	}
+	for _, r := range routes {
+		m.Routes = append(m.Routes, fromRoute(r))
+	}
+	return m
+}
+
+func fromRoute(r *route.Route) Route {
+	described := Route{
+		Name:           r.Name,
+		Method:         string(r.Method),
+		Path:           r.Path,
+		Description:    r.Description,
+		Headers:        nonEmpty(r.Headers),
+		ContentType:    r.ContentType,
+		Body:           r.Body,
+		Public:         r.Public,
+		Schema:         r.ResponseSchema,
+		SnapshotIgnore: r.SnapshotIgnore,
+	}
+	// the content type is implied for bodies
+	if r.Body != "" && r.ContentType == "application/json" {
+		described.ContentType = ""
+	}
+	for _, p := range r.Params {
+		if p.Default != "" {
+			if described.Params == nil {
+				described.Params = make(map[string]string)
+			}
+			described.Params[p.Name] = p.Default
+		}
+	}
+	if !r.Expect.IsZero() {
+		expect := &Expect{Status: r.Expect.Status, Headers: nonEmpty(r.Expect.Headers)}
+		for _, check := range r.Expect.Body {
+			expect.JSON = append(expect.JSON, check.String())
+		}
+		if r.Expect.MaxDuration > 0 {
+			expect.MaxDuration = r.Expect.MaxDuration.String()
+		}
+		described.Expect = expect
+	}
+	for _, e := range r.Extract {
+		described.Extract = append(described.Extract, e.String())
+	}
+	if !r.Example.IsZero() {
+		described.Example = &Example{Status: r.Example.Status, Headers: nonEmpty(r.Example.Headers), Body: r.Example.Body}
+	}
+	if len(described.SnapshotIgnore) == 0 {
+		described.SnapshotIgnore = nil
+	}
+	return described
+}
+
+// nonEmpty returns nil for empty maps, so they compare equal to missing ones
+func nonEmpty[M ~map[string]string](m M) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	return maps.Clone(map[string]string(m))
+}
@@ -0,0 +1,189 @@
+package manifest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sample = `
+project:
+  name: Pets
+  base_url: https://api.example.com
+environments:
+  - name: staging
+    base_url: https://staging.example.com
+    variables: {token: abc}
+    active: true
+routes:
+  - name: Create pet
+    method: post
+    path: /pets
+    body: '{"name":"Rex"}'
+    expect:
+      status: "201"
+      json: ["$.id exists"]
+      max_duration: 500ms
+    extract: ["pet_id=$.id"]
+  - name: Get pet
+    method: GET
+    path: /pets/{id}
+    params: {id: "{{pet_id}}"}
+    headers: {accept: application/json}
+`
+
+func TestParse(t *testing.T) {
+	m, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	p, envs, routes, err := m.Models()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if p.Name != "Pets" || len(envs) != 1 || !envs[0].Active || len(routes) != 2 {
+		t.Fatalf("unexpected models: %+v %+v %+v", p, envs, routes)
+	}
+
+	create, get := routes[0], routes[1]
+	if create.Method != "POST" || create.ContentType != "application/json" || create.Order != 1 {
+		t.Errorf("unexpected create route: %+v", create)
+	}
+	if create.Expect.MaxDuration != 500*time.Millisecond || len(create.Expect.Body) != 1 || len(create.Extract) != 1 {
+		t.Errorf("unexpected create assertions: %+v %+v", create.Expect, create.Extract)
+	}
+	if get.Params[0].Default != "{{pet_id}}" || get.Headers["Accept"] != "application/json" || get.Order != 2 {
+		t.Errorf("unexpected get route: %+v", get)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":     "project: {name: x}\nroutes:\n  - {name: a, method: GET, path: /a, expcet: {}}",
+		"missing project": "routes: []",
+		"duplicate route": "project: {name: x}\nroutes:\n  - {name: a, method: GET, path: /a}\n  - {name: a, method: GET, path: /b}",
+		"bad method":      "project: {name: x}\nroutes:\n  - {name: a, method: FETCH, path: /a}",
+		"bad check":       "project: {name: x}\nroutes:\n  - {name: a, method: GET, path: /a, expect: {json: [nope]}}",
+		"two active":      "project: {name: x}\nenvironments:\n  - {name: a, active: true}\n  - {name: b, active: true}",
+	}
+	for name, data := range tests {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	m, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	p, envs, routes, err := m.Models()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var buf bytes.Buffer
+	if err := FromModels(p, envs, routes).Write(&buf); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	dumped, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("expected dump to parse, got %v\n%s", err, buf.String())
+	}
+	changes, err := Plan(dumped, m, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes after round trip, got %v", changes)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	current, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	current, _ = canonical(current)
+
+	desired, _ := Parse([]byte(sample))
+	desired.Environments = nil
+	desired.Routes[0], desired.Routes[1] = desired.Routes[1], desired.Routes[0]
+	desired.Routes[0].Path = "/pets/{id}?full=1"
+	desired.Routes = append(desired.Routes, Route{Name: "List pets", Method: "GET", Path: "/pets"})
+
+	changes, err := Plan(current, desired, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{
+		"- environment staging",
+		"~ route Get pet (path)",
+		"+ route List pets",
+		"~ route Create pet (order)",
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, c.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	changes, _ = Plan(nil, desired, nil)
+	if len(changes) != 4 || changes[0].Action != Create || changes[0].Kind != "project" {
+		t.Errorf("expected project and routes to be created, got %v", changes)
+	}
+}
+
+func TestLoadResolvesSchemaFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "user.json"), []byte(`{"type": "object"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data := "project: {name: x}\nroutes:\n  - {name: a, method: GET, path: /a, schema: 'user.json#/'}\n"
+	if err := os.WriteFile(filepath.Join(dir, DefaultFile), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Load(filepath.Join(dir, DefaultFile))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := filepath.Join(dir, "user.json") + "#/"; m.Routes[0].Schema != want {
+		t.Errorf("expected schema resolved to %s, got %s", want, m.Routes[0].Schema)
+	}
+	m.RelativeTo(dir)
+	if m.Routes[0].Schema != "user.json#/" {
+		t.Errorf("expected schema relative to the manifest, got %s", m.Routes[0].Schema)
+	}
+}
+
+func TestPlanBlocksDeletesOfUsedRoutes(t *testing.T) {
+	current, err := Parse([]byte(sample))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	current, _ = canonical(current)
+	desired, _ := Parse([]byte(sample))
+	desired.Routes = desired.Routes[1:]
+
+	changes, err := Plan(current, desired, map[string][]string{
+		"Create pet": {"auth login", "suite 'checkout'"},
+		"Get pet":    {"suite 'checkout'"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	blocked := Blocked(changes)
+	if len(blocked) != 1 || blocked[0].Name != "Create pet" {
+		t.Fatalf("expected only the deleted route to be blocked, got %v", blocked)
+	}
+	if want := "- route Create pet ! used by auth login, suite 'checkout'"; blocked[0].String() != want {
+		t.Errorf("expected %q, got %q", want, blocked[0].String())
+	}
+}
@@ -0,0 +1,179 @@
+package manifest
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is one difference between the stored project and a manifest
+type Change struct {
+	Action Action
+	Kind   string // project, environment or route
+	Name   string
+	Fields []string // manifest keys that differ, for updates
+	UsedBy []string // what still references a deleted route, outside the manifest
+}
+
+func (c Change) String() string {
+	symbol := map[Action]string{Create: "+", Update: "~", Delete: "-"}[c.Action]
+	s := fmt.Sprintf("%s %s %s", symbol, c.Kind, c.Name)
+	if len(c.Fields) > 0 {
+		s += " (" + strings.Join(c.Fields, ", ") + ")"
+	}
+	if len(c.UsedBy) > 0 {
+		s += " ! used by " + strings.Join(c.UsedBy, ", ")
+	}
+	return s
+}
+
+// Blocked returns the deletes of routes that something outside the manifest still uses
+func Blocked(changes []Change) []Change {
+	var blocked []Change
+	for _, c := range changes {
+		if len(c.UsedBy) > 0 {
+			blocked = append(blocked, c)
+		}
+	}
+	return blocked
+}
+
+// Plan lists the changes that turn current into desired. current is nil when the
+// project does not exist yet. Environments and routes are matched by name, and
+// anything missing from desired is deleted. usedBy lists what references each stored
+// route outside the manifest, such as the auth profile and suites; deletes of those
+// routes carry it in UsedBy.
+func Plan(current, desired *Manifest, usedBy map[string][]string) ([]Change, error) {
+	// compare what would be stored, not how the manifest happens to spell it
+	desired, err := canonical(desired)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		changes := []Change{{Action: Create, Kind: "project", Name: desired.Project.Name}}
+		for _, e := range desired.Environments {
+			changes = append(changes, Change{Action: Create, Kind: "environment", Name: e.Name})
+		}
+		for _, r := range desired.Routes {
+			changes = append(changes, Change{Action: Create, Kind: "route", Name: r.Name})
+		}
+		return changes, nil
+	}
+
+	var changes []Change
+	if fields := differences(current.Project, desired.Project); len(fields) > 0 {
+		changes = append(changes, Change{Action: Update, Kind: "project", Name: desired.Project.Name, Fields: fields})
+	}
+	changes = append(changes, planList("environment", current.Environments, desired.Environments, func(e Environment) string { return e.Name })...)
+
+	routeChanges := planList("route", current.Routes, desired.Routes, func(r Route) string { return r.Name })
+	for _, name := range moved(current.Routes, desired.Routes) {
+		i := slices.IndexFunc(routeChanges, func(c Change) bool { return c.Name == name })
+		if i < 0 {
+			routeChanges = append(routeChanges, Change{Action: Update, Kind: "route", Name: name})
+			i = len(routeChanges) - 1
+		}
+		routeChanges[i].Fields = append(routeChanges[i].Fields, "order")
+	}
+	for i, c := range routeChanges {
+		if c.Action == Delete {
+			routeChanges[i].UsedBy = usedBy[c.Name]
+		}
+	}
+	return append(changes, routeChanges...), nil
+}
+
+func canonical(m *Manifest) (*Manifest, error) {
+	p, envs, routes, err := m.Models()
+	if err != nil {
+		return nil, err
+	}
+	return FromModels(p, envs, routes), nil
+}
+
+func planList[T any](kind string, current, desired []T, name func(T) string) []Change {
+	var changes []Change
+	for _, d := range desired {
+		i := slices.IndexFunc(current, func(c T) bool { return name(c) == name(d) })
+		if i < 0 {
+			changes = append(changes, Change{Action: Create, Kind: kind, Name: name(d)})
+			continue
+		}
+		if fields := differences(current[i], d); len(fields) > 0 {
+			changes = append(changes, Change{Action: Update, Kind: kind, Name: name(d), Fields: fields})
+		}
+	}
+	for _, c := range current {
+		if !slices.ContainsFunc(desired, func(d T) bool { return name(d) == name(c) }) {
+			changes = append(changes, Change{Action: Delete, Kind: kind, Name: name(c)})
+		}
+	}
+	return changes
+}
+
+// differences returns the yaml keys of the fields that differ between a and b
+func differences(a, b any) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	var fields []string
+	for i := range va.NumField() {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			key, _, _ := strings.Cut(va.Type().Field(i).Tag.Get("yaml"), ",")
+			fields = append(fields, key)
+		}
+	}
+	return fields
+}
+
+// moved returns the routes kept by desired that change position relative to the
+// other kept routes: those outside the longest run of names in the same order
+func moved(current, desired []Route) []string {
+	kept := func(routes, others []Route) []string {
+		var names []string
+		for _, r := range routes {
+			if slices.ContainsFunc(others, func(o Route) bool { return o.Name == r.Name }) {
+				names = append(names, r.Name)
+			}
+		}
+		return names
+	}
+	before, after := kept(current, desired), kept(desired, current)
+
+	// lengths[i][j] is the longest common subsequence of before[i:] and after[j:]
+	lengths := make([][]int, len(before)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	var names []string
+	i, j := 0, 0
+	for j < len(after) {
+		switch {
+		case i < len(before) && before[i] == after[j]:
+			i++
+			j++
+		case i < len(before) && lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			names = append(names, after[j])
+			j++
+		}
+	}
+	return names
+}
@@ -0,0 +1,186 @@
+// Package mock serves a project's routes from their example responses
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// Server answers requests with the example response of the matching route
+type Server struct {
+	prefix string // path of the project's base URL, stripped from requests
+	routes []entry
+	log    *log.Logger
+	CORS   bool // allow cross-origin requests from browsers
+}
+
+type entry struct {
+	route    *route.Route
+	segments []segment
+	literals int // number of fixed segments, used to prefer the most specific route
+}
+
+// segment matches one path segment, either literally or, when it holds parameters
+// or variables, with a pattern
+type segment struct {
+	literal string
+	pattern *regexp.Regexp
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{[^}]*\}\}|\{[^}]*\}`)
+
+// New creates a server for routes. baseURL is the project's base URL; its path is
+// treated as a prefix of every route path.
+func New(baseURL string, routes []*route.Route, logger *log.Logger) *Server {
+	s := &Server{log: logger}
+	if u, err := url.Parse(baseURL); err == nil {
+		s.prefix = strings.TrimSuffix(u.Path, "/")
+	}
+	for _, r := range routes {
+		e := entry{route: r, segments: splitTemplate(r.Path)}
+		for _, seg := range e.segments {
+			if seg.pattern == nil {
+				e.literals++
+			}
+		}
+		s.routes = append(s.routes, e)
+	}
+	// most specific routes first, so /users/me wins over /users/{id}
+	slices.SortStableFunc(s.routes, func(a, b entry) int { return b.literals - a.literals })
+	return s
+}
+
+// splitTemplate splits a route path into segments, ignoring any query string
+func splitTemplate(path string) []segment {
+	path, _, _ = strings.Cut(path, "?")
+	var segments []segment
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if !placeholderPattern.MatchString(part) {
+			segments = append(segments, segment{literal: part})
+			continue
+		}
+		var expr strings.Builder
+		expr.WriteString("^")
+		last := 0
+		for _, loc := range placeholderPattern.FindAllStringIndex(part, -1) {
+			expr.WriteString(regexp.QuoteMeta(part[last:loc[0]]))
+			expr.WriteString("[^/]+")
+			last = loc[1]
+		}
+		expr.WriteString(regexp.QuoteMeta(part[last:]) + "$")
+		segments = append(segments, segment{pattern: regexp.MustCompile(expr.String())})
+	}
+	return segments
+}
+
+func (e entry) matches(parts []string) bool {
+	if len(parts) != len(e.segments) {
+		return false
+	}
+	for i, seg := range e.segments {
+		if seg.pattern != nil && !seg.pattern.MatchString(parts[i]) {
+			return false
+		}
+		if seg.pattern == nil && seg.literal != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns the route for method and path, and the methods allowed on the path
+func (s *Server) Match(method, path string) (*route.Route, []string) {
+	path = strings.TrimPrefix(path, s.prefix)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var allowed []string
+	for _, e := range s.routes {
+		if !e.matches(segments) {
+			continue
+		}
+		if string(e.route.Method) == method {
+			return e.route, nil
+		}
+		if !slices.Contains(allowed, string(e.route.Method)) {
+			allowed = append(allowed, string(e.route.Method))
+		}
+	}
+	return nil, allowed
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	start := time.Now()
+	status, name := s.serve(w, req)
+	if s.log != nil {
+		s.log.Printf("%s %s -> %d %s (%v)", req.Method, req.URL.RequestURI(), status, name, time.Since(start).Round(time.Microsecond))
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, req *http.Request) (int, string) {
+	if s.CORS {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		if req.Method == http.MethodOptions && req.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			if headers := req.Header.Get("Access-Control-Request-Headers"); headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return http.StatusNoContent, "(preflight)"
+		}
+	}
+	r, allowed := s.Match(req.Method, req.URL.Path)
+	if r == nil {
+		status := http.StatusNotFound
+		message := fmt.Sprintf("no mock route for %s %s", req.Method, req.URL.Path)
+		if len(allowed) > 0 {
+			status = http.StatusMethodNotAllowed
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			message = fmt.Sprintf("method %s not allowed, use %s", req.Method, strings.Join(allowed, ", "))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+		return status, "(unmatched)"
+	}
+
+	example := r.Example
+	for name, value := range example.Headers {
+		w.Header().Set(name, value)
+	}
+	if w.Header().Get("Content-Type") == "" && example.Body != "" {
+		contentType := "text/plain; charset=utf-8"
+		if json.Valid([]byte(example.Body)) {
+			contentType = "application/json"
+		}
+		w.Header().Set("Content-Type", contentType)
+	}
+	status := ExampleStatus(r)
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(example.Body))
+	return status, r.Name
+}
+
+// ExampleStatus is the status served for r: its example status, else its exact expected
+// status, else 200
+func ExampleStatus(r *route.Route) int {
+	if r.Example.Status != 0 {
+		return r.Example.Status
+	}
+	if min, max, err := assert.ParseStatus(r.Expect.Status); err == nil && min == max {
+		return min
+	}
+	if code, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(r.Expect.Status), "xx")); err == nil && code >= 1 && code <= 5 {
+		return code * 100
+	}
+	return http.StatusOK
+}
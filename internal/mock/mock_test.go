@@ -0,0 +1,87 @@
+package mock
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+func newServer() *Server {
+	routes := []*route.Route{
+		{Name: "Get User", Method: route.GET, Path: "/users/{id}", Example: route.Example{Body: `{"id": 1}`}},
+		{Name: "Me", Method: route.GET, Path: "/users/me", Example: route.Example{Body: "me", Headers: map[string]string{"X-Mock": "1"}}},
+		{Name: "Create User", Method: route.POST, Path: "/users", Expect: assert.Expectation{Status: "201"}},
+		{Name: "Delete User", Method: route.DELETE, Path: "/users/{id}", Expect: assert.Expectation{Status: "2xx"}},
+		{Name: "Report", Method: route.GET, Path: "/reports/{year}-{month}.csv?full=1"},
+	}
+	return New("https://api.example.com/v1/", routes, nil)
+}
+
+func TestServe(t *testing.T) {
+	server := newServer()
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+		contentType  string
+	}{
+		{"GET", "/v1/users/42", 200, `{"id": 1}`, "application/json"},
+		{"GET", "/v1/users/me", 200, "me", "text/plain; charset=utf-8"},
+		{"POST", "/v1/users", 201, "", ""},
+		{"DELETE", "/v1/users/7", 200, "", ""},
+		{"PUT", "/v1/users/7", 405, "", "application/json"},
+		{"GET", "/v1/orders", 404, "", "application/json"},
+		{"GET", "/v1/reports/2026-10.csv", 200, "", ""},
+		{"GET", "/v1/reports/latest", 404, "", "application/json"},
+	}
+	for _, tt := range tests {
+		// Arrange
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		server.ServeHTTP(rec, req)
+
+		// Assert
+		resp := rec.Result()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, resp.StatusCode)
+		}
+		if tt.body != "" && string(body) != tt.body {
+			t.Errorf("%s %s: expected body %s, got %s", tt.method, tt.path, tt.body, body)
+		}
+		if got := resp.Header.Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s %s: expected content type %q, got %q", tt.method, tt.path, tt.contentType, got)
+		}
+	}
+}
+
+func TestServeMethodNotAllowed(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	newServer().ServeHTTP(rec, httptest.NewRequest("PATCH", "/v1/users/1", nil))
+
+	if allow := rec.Header().Get("Allow"); allow != "GET, DELETE" {
+		t.Errorf("expected Allow header with the route methods, got %q", allow)
+	}
+}
+
+func TestServeCORSPreflight(t *testing.T) {
+	server := newServer()
+	server.CORS = true
+	req := httptest.NewRequest(http.MethodOptions, "/v1/users", nil)
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	rec := httptest.NewRecorder()
+
+	server.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "*" || rec.Header().Get("Access-Control-Allow-Headers") != "Content-Type" {
+		t.Errorf("unexpected preflight response: %d %v", rec.Code, rec.Header())
+	}
+}
@@ -0,0 +1,472 @@
+package openapi
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/snapshot"
+)
+
+// ExportVersion is the OpenAPI version of exported documents
+const ExportVersion = "3.1.0"
+
+// Spec is an exported OpenAPI document. Fields are ordered as they are usually
+// written; paths and methods are sorted.
+type Spec struct {
+	OpenAPI    string                               `json:"openapi" yaml:"openapi"`
+	Info       Info                                 `json:"info" yaml:"info"`
+	Servers    []Server                             `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Security   []map[string][]string                `json:"security,omitempty" yaml:"security,omitempty"`
+	Paths      map[string]map[string]*SpecOperation `json:"paths" yaml:"paths"`
+	Components *Components                          `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title" yaml:"title"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Version     string `json:"version" yaml:"version"`
+}
+
+type Server struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]map[string]any `json:"securitySchemes,omitempty" yaml:"securitySchemes,omitempty"`
+}
+
+// SpecOperation is an operation of an exported document
+type SpecOperation struct {
+	OperationID string                    `json:"operationId" yaml:"operationId"`
+	Summary     string                    `json:"summary,omitempty" yaml:"summary,omitempty"`
+	Description string                    `json:"description,omitempty" yaml:"description,omitempty"`
+	Parameters  []SpecParameter           `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody map[string]any            `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]map[string]any `json:"responses" yaml:"responses"`
+	Security    *[]map[string][]string    `json:"security,omitempty" yaml:"security,omitempty"`
+}
+
+// SpecParameter is a parameter of an exported operation
+type SpecParameter struct {
+	Name     string         `json:"name" yaml:"name"`
+	In       string         `json:"in" yaml:"in"`
+	Required bool           `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   map[string]any `json:"schema" yaml:"schema"`
+	Example  any            `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+// Recorded is a response seen for a route, used to describe its response body
+type Recorded struct {
+	Status      int
+	ContentType string
+	Body        string
+}
+
+// ExportOptions describes what goes into an exported document besides the routes
+type ExportOptions struct {
+	Servers  []Server          // servers after the project's base URL
+	Auth     *auth.Profile     // the project's auth profile, nil for none
+	Recorded map[uint]Recorded // recorded responses by route ID, used when a route has no example
+}
+
+// Export builds an OpenAPI 3.1 document from a project and its routes. Request and
+// response schemas are inferred from stored bodies, examples and recorded responses.
+// Routes that repeat an earlier route's method and path are left out and returned.
+func Export(p *project.Project, routes []*route.Route, opts ExportOptions) (*Spec, []*route.Route) {
+	spec := &Spec{
+		OpenAPI: ExportVersion,
+		Info:    Info{Title: p.Name, Description: p.Description, Version: "1.0.0"},
+		Paths:   make(map[string]map[string]*SpecOperation),
+	}
+	for _, s := range append([]Server{{URL: p.BaseURL}}, opts.Servers...) {
+		if s.URL != "" && !slices.ContainsFunc(spec.Servers, func(o Server) bool { return o.URL == s.URL }) {
+			s.URL = templateVars.ReplaceAllString(s.URL, "{$1}")
+			spec.Servers = append(spec.Servers, s)
+		}
+	}
+	scheme := securityScheme(opts.Auth)
+	if scheme != nil {
+		spec.Components = &Components{SecuritySchemes: map[string]map[string]any{"default": scheme}}
+		spec.Security = []map[string][]string{{"default": {}}}
+	}
+
+	var skipped []*route.Route
+	used := make(map[string]int)
+	for _, r := range routes {
+		path, query, _ := strings.Cut(r.Path, "?")
+		path = templateVars.ReplaceAllString(path, "{$1}")
+		method := strings.ToLower(string(r.Method))
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = make(map[string]*SpecOperation)
+		}
+		if spec.Paths[path][method] != nil {
+			skipped = append(skipped, r)
+			continue
+		}
+		op := &SpecOperation{
+			OperationID: operationID(r.Name, used),
+			Summary:     r.Name,
+			Description: r.Description,
+			Parameters:  parameters(r, path, query),
+			Responses:   make(map[string]map[string]any),
+		}
+		if r.Body != "" {
+			op.RequestBody = map[string]any{"content": content(cmp.Or(r.ContentType, "application/json"), r.Body)}
+		}
+		status, resp := response(r, opts.Recorded)
+		op.Responses[status] = resp
+		if r.Public && scheme != nil {
+			op.Security = &[]map[string][]string{}
+		}
+		spec.Paths[path][method] = op
+	}
+	return spec, skipped
+}
+
+// templateVars matches {{name}} variables, written as {name} in the exported document
+var templateVars = regexp.MustCompile(`\{\{\s*([\w.-]+)\s*\}\}`)
+
+// operationID turns a route name into a unique camelCase identifier
+func operationID(name string, used map[string]int) string {
+	words := strings.FieldsFunc(name, func(c rune) bool { return !unicode.IsLetter(c) && !unicode.IsDigit(c) })
+	var b strings.Builder
+	for i, word := range words {
+		r := []rune(word)
+		if i == 0 {
+			// keep camelCase names such as getPet, but write acronyms such as GET in lowercase
+			if strings.ToUpper(word) == word {
+				b.WriteString(strings.ToLower(word))
+			} else {
+				b.WriteString(strings.ToLower(string(r[0])) + string(r[1:]))
+			}
+			continue
+		}
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	id := cmp.Or(b.String(), "operation")
+	used[id]++
+	if n := used[id]; n > 1 {
+		id += strconv.Itoa(n)
+	}
+	return id
+}
+
+// parameters describes the route's path and query parameters and custom headers
+func parameters(r *route.Route, path, query string) []SpecParameter {
+	var params []SpecParameter
+	defaults := make(map[string]string, len(r.Params))
+	for _, p := range r.Params {
+		defaults[p.Name] = p.Default
+	}
+	for _, match := range pathParams.FindAllStringSubmatch(path, -1) {
+		param := SpecParameter{Name: match[1], In: "path", Required: true, Schema: map[string]any{"type": "string"}}
+		if value := defaults[match[1]]; value != "" && !strings.Contains(value, "{{") {
+			param.Schema, param.Example = inferScalar(value)
+		}
+		params = append(params, param)
+	}
+	values, _ := url.ParseQuery(query)
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		param := SpecParameter{Name: name, In: "query"}
+		param.Schema, param.Example = inferScalar(values.Get(name))
+		params = append(params, param)
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+		if name == "Authorization" || name == "Accept" || name == "User-Agent" {
+			continue
+		}
+		params = append(params, SpecParameter{Name: name, In: "header", Schema: map[string]any{"type": "string"}, Example: r.Headers[name]})
+	}
+	return params
+}
+
+var pathParams = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// response picks the documented status of a route and describes its response
+func response(r *route.Route, recorded map[uint]Recorded) (string, map[string]any) {
+	status := "200"
+	if min, max, err := assert.ParseStatus(r.Expect.Status); r.Expect.Status != "" && err == nil {
+		status = strconv.Itoa(min)
+		if max-min == 99 && min%100 == 0 {
+			status = status[:1] + "XX"
+		}
+	}
+	rec := Recorded{Status: r.Example.Status, ContentType: r.Example.Headers["Content-Type"], Body: r.Example.Body}
+	if r.Example.IsZero() {
+		rec = recorded[r.ID]
+	}
+	if rec.Status != 0 {
+		status = strconv.Itoa(rec.Status)
+	}
+	description := "Response"
+	if code, err := strconv.Atoi(status); err == nil && http.StatusText(code) != "" {
+		description = http.StatusText(code)
+	}
+	resp := map[string]any{"description": description}
+	if rec.Body != "" {
+		resp["content"] = content(cmp.Or(rec.ContentType, "application/json"), rec.Body)
+	}
+	return status, resp
+}
+
+// content describes a body under its media type, with an inferred schema for JSON
+func content(mediaType, body string) map[string]any {
+	media := map[string]any{}
+	if doc, err := jsonpath.Decode([]byte(body)); err == nil {
+		media["schema"] = Infer(doc)
+		media["example"] = plain(doc)
+	} else {
+		media["schema"] = map[string]any{"type": "string"}
+		media["example"] = body
+	}
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	return map[string]any{strings.TrimSpace(mediaType): media}
+}
+
+// plain converts json.Number values to Go numbers so they encode as numbers in YAML too
+func plain(v any) any {
+	switch t := v.(type) {
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			return n
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]any:
+		for key, value := range t {
+			t[key] = plain(value)
+		}
+	case []any:
+		for i, value := range t {
+			t[i] = plain(value)
+		}
+	}
+	return v
+}
+
+// securityScheme describes an auth profile, or returns nil for none
+func securityScheme(p *auth.Profile) map[string]any {
+	if p == nil {
+		return nil
+	}
+	switch p.Type {
+	case auth.Bearer:
+		return map[string]any{"type": "http", "scheme": "bearer"}
+	case auth.JWT:
+		return map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"}
+	case auth.Basic:
+		return map[string]any{"type": "http", "scheme": "basic"}
+	case auth.APIKey:
+		in := "header"
+		if p.KeyIn == auth.InQuery {
+			in = "query"
+		}
+		return map[string]any{"type": "apiKey", "name": p.KeyName, "in": in}
+	}
+	return nil
+}
+
+// Infer builds a JSON Schema describing a decoded JSON value. Objects list their
+// keys as required; array item schemas are merged across elements.
+func Infer(v any) map[string]any {
+	switch t := v.(type) {
+	case nil:
+		return map[string]any{"type": "null"}
+	case bool:
+		return map[string]any{"type": "boolean"}
+	case json.Number:
+		if _, err := t.Int64(); err == nil {
+			return map[string]any{"type": "integer"}
+		}
+		return map[string]any{"type": "number"}
+	case float64:
+		if t == float64(int64(t)) {
+			return map[string]any{"type": "integer"}
+		}
+		return map[string]any{"type": "number"}
+	case string:
+		if t == snapshot.Ignored {
+			return map[string]any{}
+		}
+		return stringSchema(t)
+	case []any:
+		schema := map[string]any{"type": "array"}
+		var items map[string]any
+		for _, item := range t {
+			items = merge(items, Infer(item))
+		}
+		if items != nil {
+			schema["items"] = items
+		}
+		return schema
+	case map[string]any:
+		props := make(map[string]any, len(t))
+		for key, value := range t {
+			props[key] = Infer(value)
+		}
+		schema := map[string]any{"type": "object", "properties": props}
+		if len(t) > 0 {
+			schema["required"] = slices.Sorted(maps.Keys(t))
+		}
+		return schema
+	}
+	return map[string]any{}
+}
+
+// inferScalar infers the schema of a parameter value given as text and converts
+// the value to match
+func inferScalar(value string) (map[string]any, any) {
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return map[string]any{"type": "integer"}, n
+	}
+	return stringSchema(value), value
+}
+
+var (
+	uuidValue = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	dateValue = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	timeValue = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}`)
+	mailValue = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[a-zA-Z]+$`)
+)
+
+// stringSchema describes a string, recognizing common formats
+func stringSchema(s string) map[string]any {
+	schema := map[string]any{"type": "string"}
+	switch {
+	case uuidValue.MatchString(s):
+		schema["format"] = "uuid"
+	case timeValue.MatchString(s):
+		schema["format"] = "date-time"
+	case dateValue.MatchString(s):
+		schema["format"] = "date"
+	case mailValue.MatchString(s):
+		schema["format"] = "email"
+	case strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://"):
+		schema["format"] = "uri"
+	}
+	return schema
+}
+
+// merge combines the schemas of two values seen in the same place. Objects keep
+// every property and require only those present in both; other types that differ
+// become a type list.
+func merge(a, b map[string]any) map[string]any {
+	if a == nil {
+		return b
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return map[string]any{}
+	}
+	at, bt := types(a), types(b)
+	if slices.Equal(at, bt) && len(at) == 1 {
+		switch at[0] {
+		case "object":
+			ap, bp := obj(a["properties"]), obj(b["properties"])
+			props := maps.Clone(ap)
+			for key, schema := range bp {
+				if existing, ok := props[key]; ok {
+					props[key] = merge(obj(existing), obj(schema))
+				} else {
+					props[key] = schema
+				}
+			}
+			merged := map[string]any{"type": "object", "properties": props}
+			var required []string
+			for _, key := range strs(a["required"]) {
+				if slices.Contains(strs(b["required"]), key) {
+					required = append(required, key)
+				}
+			}
+			if len(required) > 0 {
+				merged["required"] = required
+			}
+			return merged
+		case "array":
+			merged := map[string]any{"type": "array"}
+			if items := merge(obj(a["items"]), obj(b["items"])); items != nil {
+				merged["items"] = items
+			}
+			return merged
+		}
+		if a["format"] != b["format"] {
+			return map[string]any{"type": at[0]}
+		}
+		return a
+	}
+	union := sortedUnion(at, bt)
+	if slices.Equal(union, []string{"integer", "number"}) {
+		return map[string]any{"type": "number"}
+	}
+	// a value that is sometimes null keeps its schema with null added
+	base := a
+	switch {
+	case isNullOnly(at):
+		base = b
+	case isNullOnly(bt):
+	default:
+		if slices.ContainsFunc(union, func(t string) bool { return t == "object" || t == "array" }) {
+			return map[string]any{}
+		}
+		base = map[string]any{}
+	}
+	merged := maps.Clone(base)
+	list := make([]any, len(union))
+	for i, t := range union {
+		list[i] = t
+	}
+	merged["type"] = list
+	return merged
+}
+
+func types(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		return strs(t)
+	}
+	return nil
+}
+
+func sortedUnion(a, b []string) []string {
+	union := slices.Concat(a, b)
+	slices.Sort(union)
+	return slices.Compact(union)
+}
+
+func isNullOnly(types []string) bool {
+	return len(types) == 1 && types[0] == "null"
+}
+
+func strs(v any) []string {
+	switch t := v.(type) {
+	case []string:
+		return t
+	case []any:
+		var out []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
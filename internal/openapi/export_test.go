@@ -0,0 +1,106 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+func TestExport(t *testing.T) {
+	p := &project.Project{Name: "Pets", BaseURL: "https://api.example.com/v1", Description: "Pet store"}
+	routes := []*route.Route{
+		{ID: 1, Name: "List pets", Method: route.GET, Path: "/pets?limit=10", Expect: assert.Expectation{Status: "2xx"}},
+		{ID: 2, Name: "Get pet", Method: route.GET, Path: "/pets/{{pet_id}}", Public: true,
+			Example: route.Example{Status: 200, Body: `{"id": 7, "name": "Rex", "born": "2020-01-02"}`}},
+		{ID: 3, Name: "Create pet", Method: route.POST, Path: "/pets", ContentType: "application/json", Body: `{"name": "Rex"}`},
+		{ID: 4, Name: "List pets again", Method: route.GET, Path: "/pets"},
+	}
+
+	spec, skipped := Export(p, routes, ExportOptions{
+		Auth:     &auth.Profile{Type: auth.Bearer},
+		Recorded: map[uint]Recorded{1: {Body: `[{"id": 1}, {"id": 2, "tag": null}]`}},
+	})
+
+	if len(skipped) != 1 || skipped[0].ID != 4 {
+		t.Errorf("expected the repeated route to be skipped, got %v", skipped)
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatalf("expected exported document to parse, got %v", err)
+	}
+	if doc.Version != ExportVersion || doc.Servers[0] != p.BaseURL || doc.Description != p.Description {
+		t.Errorf("unexpected document header: %+v", doc)
+	}
+	if len(doc.Operations) != 3 {
+		t.Fatalf("expected 3 operations, got %d", len(doc.Operations))
+	}
+
+	get := doc.Find("GET", "/pets/{id}")
+	if get == nil || get.OperationID != "getPet" || get.Responses[0].Status != "200" {
+		t.Fatalf("expected getPet with a 200 response, got %+v", get)
+	}
+	if props := get.Responses[0].Schema["properties"].(map[string]any); props["born"].(map[string]any)["format"] != "date" {
+		t.Errorf("expected date format to be inferred, got %v", props)
+	}
+	list := doc.Find("GET", "/pets")
+	if list.Responses[0].Status != "2XX" {
+		t.Errorf("expected status class from expectation, got %s", list.Responses[0].Status)
+	}
+	items := list.Responses[0].Schema["items"].(map[string]any)
+	if required, _ := items["required"].([]any); len(required) != 1 || required[0] != "id" {
+		t.Errorf("expected only keys present in every item to be required, got %v", items["required"])
+	}
+	if spec.Paths["/pets/{pet_id}"]["get"].Security == nil {
+		t.Error("expected public route to opt out of security")
+	}
+}
+
+func TestInferMerge(t *testing.T) {
+	doc, _ := jsonpath.Decode([]byte(`[1, 2.5, null]`))
+
+	items := Infer(doc)["items"].(map[string]any)
+
+	types, _ := items["type"].([]any)
+	if len(types) != 2 || types[0] != "null" || types[1] != "number" {
+		t.Errorf("expected nullable number, got %v", items)
+	}
+}
+
+func TestExportOperationIDRoundTrip(t *testing.T) {
+	p := &project.Project{Name: "Pets", BaseURL: "https://api.example.com"}
+	routes := []*route.Route{
+		{ID: 1, Name: "getPetById", Method: route.GET, Path: "/pets/{id}"},
+		{ID: 2, Name: "GET health", Method: route.GET, Path: "/health"},
+	}
+
+	spec, _ := Export(p, routes, ExportOptions{})
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		t.Fatalf("expected exported document to parse, got %v", err)
+	}
+	imported, err := doc.Routes()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, r := range imported {
+		names[r.Name] = true
+	}
+	if !names["getPetById"] || !names["getHealth"] {
+		t.Errorf("expected getPetById to keep its name and GET health to become getHealth, got %v", names)
+	}
+}
@@ -0,0 +1,275 @@
+// Package openapi reads OpenAPI 3 and Swagger 2 documents into a version-independent form
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Methods are the HTTP methods read from path items, in document order
+var Methods = []string{"get", "post", "put", "patch", "delete"}
+
+// Document is a parsed OpenAPI or Swagger document
+type Document struct {
+	Version     string
+	Title       string
+	Description string
+	Servers     []string
+	Operations  []Operation
+	raw         map[string]any
+}
+
+// Operation is a single method on a path
+type Operation struct {
+	Method      string // upper case
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	PathParams  []Parameter
+	ContentType string // of the request body
+	RequestBody string // example request body, JSON encoded
+	Responses   []Response
+}
+
+type Parameter struct {
+	Name    string
+	Example string
+}
+
+type Response struct {
+	Status  string         // a code such as 200, a class such as 2XX, or default
+	Schema  map[string]any // JSON schema of the response body, nil if not declared
+	Example string         // JSON encoded, empty if not declared
+}
+
+// Load reads a YAML or JSON document from a file
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse reads a YAML or JSON document
+func Parse(data []byte) (*Document, error) {
+	var decoded any
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	raw := obj(schema.Normalize(decoded))
+	if raw == nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: expected an object")
+	}
+	doc := &Document{raw: raw}
+	switch {
+	case str(raw["openapi"]) != "":
+		doc.Version = str(raw["openapi"])
+		doc.Servers = serversV3(raw)
+	case str(raw["swagger"]) != "":
+		doc.Version = str(raw["swagger"])
+		doc.Servers = serversV2(raw)
+	default:
+		return nil, fmt.Errorf("invalid OpenAPI document: missing 'openapi' or 'swagger' version")
+	}
+	info := obj(raw["info"])
+	doc.Title = str(info["title"])
+	doc.Description = str(info["description"])
+
+	paths := obj(raw["paths"])
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item := doc.Resolve(obj(paths[path]))
+		for _, method := range Methods {
+			op, ok := item[method]
+			if !ok {
+				continue
+			}
+			doc.Operations = append(doc.Operations, doc.operation(path, method, item, doc.Resolve(obj(op))))
+		}
+	}
+	return doc, nil
+}
+
+// IsV2 reports whether the document is a Swagger 2 document
+func (d *Document) IsV2() bool {
+	return strings.HasPrefix(d.Version, "2")
+}
+
+// Find returns the operation for a method and path, matching parameter names loosely
+func (d *Document) Find(method, path string) *Operation {
+	for i := range d.Operations {
+		op := &d.Operations[i]
+		if op.Method == strings.ToUpper(method) && SamePath(op.Path, path) {
+			return op
+		}
+	}
+	return nil
+}
+
+// SamePath compares two templated paths, ignoring parameter names
+func SamePath(a, b string) bool {
+	as, bs := strings.Split(strings.Trim(a, "/"), "/"), strings.Split(strings.Trim(b, "/"), "/")
+	if len(as) != len(bs) {
+		return false
+	}
+	for i := range as {
+		if isParam(as[i]) && isParam(bs[i]) {
+			continue
+		}
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && !strings.HasPrefix(segment, "{{")
+}
+
+func (d *Document) operation(path, method string, item, op map[string]any) Operation {
+	o := Operation{
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		OperationID: str(op["operationId"]),
+		Summary:     str(op["summary"]),
+		Description: str(op["description"]),
+	}
+
+	// path-level parameters apply unless the operation overrides them
+	params := map[string]map[string]any{}
+	var order []string
+	for _, list := range []any{item["parameters"], op["parameters"]} {
+		for _, p := range arr(list) {
+			param := d.Resolve(obj(p))
+			key := str(param["in"]) + ":" + str(param["name"])
+			if _, ok := params[key]; !ok {
+				order = append(order, key)
+			}
+			params[key] = param
+		}
+	}
+	for _, key := range order {
+		param := params[key]
+		switch str(param["in"]) {
+		case "path":
+			example := param["example"]
+			if example == nil {
+				example = exampleFromSchema(d, d.Resolve(obj(param["schema"])), 0)
+			}
+			if example == nil {
+				example = param["default"]
+			}
+			p := Parameter{Name: str(param["name"])}
+			if example != nil {
+				p.Example = scalar(example)
+			}
+			o.PathParams = append(o.PathParams, p)
+		case "body":
+			o.ContentType = "application/json"
+			if consumes := arr(op["consumes"]); len(consumes) > 0 {
+				o.ContentType = str(consumes[0])
+			}
+			o.RequestBody = encode(exampleFromSchema(d, d.Resolve(obj(param["schema"])), 0))
+		}
+	}
+
+	if body := d.Resolve(obj(op["requestBody"])); body != nil {
+		o.ContentType, o.RequestBody = d.mediaExample(obj(body["content"]))
+	}
+
+	codes := obj(op["responses"])
+	for _, status := range slices.Sorted(maps.Keys(codes)) {
+		resp := d.Resolve(obj(codes[status]))
+		r := Response{Status: status}
+		if d.IsV2() {
+			r.Schema = d.Resolve(obj(resp["schema"]))
+			if examples := obj(resp["examples"]); len(examples) > 0 {
+				r.Example = encode(examples[slices.Sorted(maps.Keys(examples))[0]])
+			}
+		} else {
+			content := obj(resp["content"])
+			if media := obj(content[preferredMedia(content)]); media != nil {
+				r.Schema = d.Resolve(obj(media["schema"]))
+				_, r.Example = d.mediaExample(content)
+			}
+		}
+		o.Responses = append(o.Responses, r)
+	}
+	return o
+}
+
+// mediaExample picks the preferred media type of a content map and its example, encoded as JSON
+func (d *Document) mediaExample(content map[string]any) (string, string) {
+	contentType := preferredMedia(content)
+	media := obj(content[contentType])
+	if media == nil {
+		return "", ""
+	}
+	if example, ok := media["example"]; ok {
+		return contentType, encode(example)
+	}
+	if examples := obj(media["examples"]); len(examples) > 0 {
+		first := d.Resolve(obj(examples[slices.Sorted(maps.Keys(examples))[0]]))
+		return contentType, encode(first["value"])
+	}
+	example := exampleFromSchema(d, d.Resolve(obj(media["schema"])), 0)
+	if example == nil {
+		return contentType, ""
+	}
+	return contentType, encode(example)
+}
+
+func preferredMedia(content map[string]any) string {
+	if _, ok := content["application/json"]; ok {
+		return "application/json"
+	}
+	for _, media := range slices.Sorted(maps.Keys(content)) {
+		if strings.Contains(media, "json") {
+			return media
+		}
+	}
+	keys := slices.Sorted(maps.Keys(content))
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+func serversV3(raw map[string]any) []string {
+	var servers []string
+	for _, s := range arr(raw["servers"]) {
+		server := obj(s)
+		url := str(server["url"])
+		// substitute server variables with their defaults
+		for name, v := range obj(server["variables"]) {
+			url = strings.ReplaceAll(url, "{"+name+"}", str(obj(v)["default"]))
+		}
+		servers = append(servers, strings.TrimSuffix(url, "/"))
+	}
+	return servers
+}
+
+func serversV2(raw map[string]any) []string {
+	host := str(raw["host"])
+	if host == "" {
+		return nil
+	}
+	basePath := strings.TrimSuffix(str(raw["basePath"]), "/")
+	schemes := arr(raw["schemes"])
+	if len(schemes) == 0 {
+		schemes = []any{"https"}
+	}
+	var servers []string
+	for _, scheme := range schemes {
+		servers = append(servers, str(scheme)+"://"+host+basePath)
+	}
+	return servers
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"testing"
+)
+
+const specV3 = `
+openapi: 3.0.3
+info:
+  title: Pets
+  description: Pet store
+servers:
+  - url: https://{env}.example.com/v1/
+    variables:
+      env:
+        default: api
+paths:
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema:
+          type: integer
+          example: 7
+    get:
+      operationId: getPet
+      summary: Get a pet
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        404:
+          description: missing
+  /pets:
+    post:
+      summary: Create pet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: created
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+          example: Rex
+`
+
+const specV2 = `{
+  "swagger": "2.0",
+  "info": {"title": "Legacy"},
+  "host": "legacy.example.com",
+  "basePath": "/api",
+  "schemes": ["http"],
+  "paths": {
+    "/users": {
+      "post": {
+        "operationId": "createUser",
+        "parameters": [{"name": "body", "in": "body", "schema": {"$ref": "#/definitions/User"}}],
+        "responses": {"201": {"description": "created", "schema": {"$ref": "#/definitions/User"}}}
+      }
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "properties": {"email": {"type": "string", "format": "email"}}}
+  }
+}`
+
+func TestParseV3(t *testing.T) {
+	doc, err := Parse([]byte(specV3))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	p := doc.Project("")
+	if p.Name != "Pets" || p.BaseURL != "https://api.example.com/v1" {
+		t.Errorf("unexpected project: %+v", p)
+	}
+	routes, err := doc.Routes()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(routes))
+	}
+
+	create, get := routes[0], routes[1]
+	if create.Name != "Create pet" || create.Body != `{"name":"Rex"}` || create.Expect.Status != "201" {
+		t.Errorf("unexpected create route: %+v", create)
+	}
+	if get.Name != "getPet" || get.Params[0].Default != "7" || get.Expect.Status != "200" {
+		t.Errorf("unexpected get route: %+v", get)
+	}
+	if get.Example.Status != 200 || get.Example.Body != `{"name":"Rex"}` {
+		t.Errorf("expected example response from schema, got %+v", get.Example)
+	}
+	if schema := doc.Find("GET", "/pets/{id}").Responses[0].Schema; schema["type"] != "object" {
+		t.Errorf("expected resolved Pet schema, got %v", schema)
+	}
+}
+
+func TestParseV2(t *testing.T) {
+	doc, err := Parse([]byte(specV2))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if doc.Project("").BaseURL != "http://legacy.example.com/api" {
+		t.Errorf("unexpected base URL: %s", doc.Project("").BaseURL)
+	}
+	routes, err := doc.Routes()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if routes[0].Body != `{"email":"user@example.com"}` || routes[0].ContentType != "application/json" {
+		t.Errorf("unexpected body: %s (%s)", routes[0].Body, routes[0].ContentType)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	if _, err := Parse([]byte("info: {title: x}")); err == nil {
+		t.Error("expected error for document without version")
+	}
+}
@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// Project builds a project from the document's info and first server
+func (d *Document) Project(name string) *project.Project {
+	if name == "" {
+		name = d.Title
+	}
+	p := &project.Project{Name: name, Description: d.Description}
+	if p.Description == "" {
+		p.Description = d.Title
+	}
+	if len(d.Servers) > 0 {
+		p.BaseURL = d.Servers[0]
+	}
+	return p
+}
+
+// Routes converts every operation into a route with a unique name
+func (d *Document) Routes() ([]*route.Route, error) {
+	var routes []*route.Route
+	used := make(map[string]int)
+	for _, op := range d.Operations {
+		r, err := op.Route()
+		if err != nil {
+			return nil, err
+		}
+		used[r.Name]++
+		if n := used[r.Name]; n > 1 {
+			r.Name = fmt.Sprintf("%s (%d)", r.Name, n)
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+// Route converts the operation into a route
+func (op *Operation) Route() (*route.Route, error) {
+	method, err := route.ParseHTTPMethod(op.Method)
+	if err != nil {
+		return nil, err
+	}
+	defaults := make(map[string]string)
+	for _, p := range op.PathParams {
+		if p.Example != "" {
+			defaults[p.Name] = p.Example
+		}
+	}
+	params, err := route.BuildParams(op.Path, defaults)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", op.Method, op.Path, err)
+	}
+	r := &route.Route{
+		Name:        op.Name(),
+		Method:      method,
+		Path:        op.Path,
+		Params:      params,
+		Description: op.Description,
+		Body:        op.RequestBody,
+	}
+	if r.Description == "" {
+		r.Description = op.Summary
+	}
+	if r.Body != "" {
+		r.ContentType = op.ContentType
+	}
+	r.Expect.Status = op.SuccessStatus()
+	for _, resp := range op.Responses {
+		if resp.Status == r.Expect.Status && resp.Example != "" {
+			r.Example.Body = resp.Example
+			r.Example.Status, _ = strconv.Atoi(resp.Status)
+			break
+		}
+	}
+	return r, nil
+}
+
+// Name is the operationId, falling back to the summary and then to method and path
+func (op *Operation) Name() string {
+	switch {
+	case op.OperationID != "":
+		return op.OperationID
+	case op.Summary != "":
+		return op.Summary
+	default:
+		return op.Method + " " + op.Path
+	}
+}
+
+// SuccessStatus returns the first declared 2xx status, or empty if there is none
+func (op *Operation) SuccessStatus() string {
+	for _, r := range op.Responses {
+		if len(r.Status) == 3 && r.Status[0] == '2' {
+			return strings.ToLower(r.Status)
+		}
+	}
+	return ""
+}
+
+// Response returns the declared response for a status code: an exact match, then
+// its class such as 2XX, then default. It returns nil if none applies.
+func (op *Operation) Response(status int) *Response {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		for i := range op.Responses {
+			if strings.EqualFold(op.Responses[i].Status, key) {
+				return &op.Responses[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Statuses lists the declared response statuses
+func (op *Operation) Statuses() []string {
+	statuses := make([]string, len(op.Responses))
+	for i, r := range op.Responses {
+		statuses[i] = r.Status
+	}
+	return statuses
+}
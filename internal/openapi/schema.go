@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/schema"
+)
+
+// Resolve follows local $ref pointers such as #/components/schemas/User
+func (d *Document) Resolve(node map[string]any) map[string]any {
+	for range schema.MaxRefDepth {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+		node = d.Pointer(ref)
+	}
+	return node
+}
+
+// Pointer returns the object at a local JSON pointer such as #/components/schemas/User
+func (d *Document) Pointer(ref string) map[string]any {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+	node, _ := schema.ResolvePointer(d.raw, "/"+pointer)
+	return obj(node)
+}
+
+// Raw returns the decoded document, for resolving references from schemas
+func (d *Document) Raw() map[string]any {
+	return d.raw
+}
+
+// exampleFromSchema builds a sample value for a schema, preferring declared examples
+func exampleFromSchema(d *Document, node map[string]any, depth int) any {
+	if node == nil || depth > schema.MaxRefDepth {
+		return nil
+	}
+	node = d.Resolve(node)
+	if example, ok := node["example"]; ok {
+		return example
+	}
+	if def, ok := node["default"]; ok {
+		return def
+	}
+	if enum := arr(node["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if options := arr(node[key]); len(options) > 0 {
+			if key != "allOf" {
+				return exampleFromSchema(d, obj(options[0]), depth+1)
+			}
+			merged := map[string]any{}
+			for _, option := range options {
+				if value, ok := exampleFromSchema(d, obj(option), depth+1).(map[string]any); ok {
+					maps.Copy(merged, value)
+				}
+			}
+			return merged
+		}
+	}
+	switch schemaType(node) {
+	case "object":
+		props := obj(node["properties"])
+		result := make(map[string]any, len(props))
+		for name, prop := range props {
+			result[name] = exampleFromSchema(d, obj(prop), depth+1)
+		}
+		return result
+	case "array":
+		item := exampleFromSchema(d, obj(node["items"]), depth+1)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case "string":
+		switch str(node["format"]) {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		}
+		return "string"
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return false
+	}
+	return nil
+}
+
+// schemaType returns the schema's type, inferring object from properties.
+// OpenAPI 3.1 type arrays use their first non-null entry.
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s := str(v); s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func obj(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func arr(v any) []any {
+	a, _ := v.([]any)
+	return a
+}
+
+func str(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(s)
+	}
+}
+
+// scalar formats a parameter example as a string
+func scalar(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return encode(v)
+}
+
+func encode(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,249 @@
+package postman
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// folderSeparator joins folder and request names into route names
+const folderSeparator = " / "
+
+// baseURLVariables are the collection variables recognised as the base URL
+var baseURLVariables = []string{"baseUrl", "baseURL", "base_url", "url", "host"}
+
+// Import is the result of converting a collection
+type Import struct {
+	Project   *project.Project
+	Routes    []*route.Route
+	Variables map[string]string // collection variables other than the base URL
+	Skipped   []string          // requests that could not be converted, with the reason
+}
+
+var pathVarPattern = regexp.MustCompile(`(^|/):([A-Za-z0-9_.-]+)`)
+
+// Convert maps the collection to a project and its routes. Requests in folders are
+// named "Folder / Request". baseURL overrides the base URL found in the collection.
+func (c *Collection) Convert(baseURL string) (*Import, error) {
+	result := &Import{
+		Project:   &project.Project{Name: c.Info.Name, Description: string(c.Info.Description)},
+		Variables: make(map[string]string),
+	}
+	baseVar := ""
+	for _, v := range c.Variable {
+		if v.Disabled {
+			continue
+		}
+		if baseVar == "" && slices.Contains(baseURLVariables, v.Key) {
+			baseVar = v.Key
+			if baseURL == "" {
+				baseURL = v.Value
+			}
+			continue
+		}
+		result.Variables[v.Key] = v.Value
+	}
+
+	used := make(map[string]int)
+	var walk func(items []Item, prefix string)
+	walk = func(items []Item, prefix string) {
+		for _, item := range items {
+			name := prefix + item.Name
+			if item.Request == nil {
+				walk(item.Item, name+folderSeparator)
+				continue
+			}
+			r, base, err := convertRequest(name, item, baseVar, baseURL)
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+			if baseURL == "" {
+				baseURL = base
+			}
+			used[r.Name]++
+			if n := used[r.Name]; n > 1 {
+				r.Name = fmt.Sprintf("%s (%d)", r.Name, n)
+			}
+			result.Routes = append(result.Routes, r)
+		}
+	}
+	walk(c.Item, "")
+	result.Project.BaseURL = strings.TrimSuffix(baseURL, "/")
+	return result, nil
+}
+
+// convertRequest turns a request item into a route, returning the origin of its URL
+// when the URL does not start with the base URL variable
+func convertRequest(name string, item Item, baseVar, baseURL string) (*route.Route, string, error) {
+	req := item.Request
+	method, err := route.ParseHTTPMethod(req.Method)
+	if err != nil {
+		return nil, "", err
+	}
+	raw := req.URL.Raw
+	if raw == "" {
+		raw = strings.Join(req.URL.Host, ".") + "/" + strings.Join(req.URL.Path, "/")
+	}
+
+	var path, origin string
+	switch {
+	case baseVar != "" && strings.HasPrefix(raw, "{{"+baseVar+"}}"):
+		path = strings.TrimPrefix(raw, "{{"+baseVar+"}}")
+	case baseURL != "" && strings.HasPrefix(raw, baseURL):
+		path = strings.TrimPrefix(raw, baseURL)
+	default:
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			return nil, "", fmt.Errorf("cannot split URL '%s' into base URL and path", raw)
+		}
+		origin = u.Scheme + "://" + u.Host
+		path = strings.TrimPrefix(raw, origin)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	// Postman path variables (:id) become route parameters ({id})
+	path = pathVarPattern.ReplaceAllString(path, "$1{$2}")
+
+	defaults := make(map[string]string)
+	for _, v := range req.URL.Variable {
+		if v.Value != "" {
+			defaults[v.Key] = v.Value
+		}
+	}
+	params, err := route.BuildParams(path, defaults)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r := &route.Route{
+		Name:        name,
+		Method:      method,
+		Path:        path,
+		Params:      params,
+		Headers:     make(route.Headers),
+		Description: string(req.Description),
+	}
+	if r.Description == "" {
+		r.Description = string(item.Description)
+	}
+	for _, h := range req.Header {
+		if h.Disabled {
+			continue
+		}
+		if strings.EqualFold(h.Key, "Content-Type") {
+			r.ContentType = h.Value
+			continue
+		}
+		r.Headers[http.CanonicalHeaderKey(h.Key)] = h.Value
+	}
+	if req.Body != nil {
+		switch req.Body.Mode {
+		case "raw":
+			r.Body = req.Body.Raw
+			if r.ContentType == "" && req.Body.Options != nil && req.Body.Options.Raw.Language == "json" {
+				r.ContentType = "application/json"
+			}
+		case "urlencoded":
+			form := url.Values{}
+			for _, v := range req.Body.URLEncoded {
+				if !v.Disabled {
+					form.Add(v.Key, v.Value)
+				}
+			}
+			r.Body = form.Encode()
+			if r.ContentType == "" {
+				r.ContentType = "application/x-www-form-urlencoded"
+			}
+		}
+	}
+	return r, origin, nil
+}
+
+// Export builds a collection from a project, its routes and variables. The project's
+// base URL is exported as the baseUrl variable.
+func Export(p *project.Project, routes []*route.Route, vars map[string]string) *Collection {
+	c := &Collection{
+		Info: Info{
+			Name:        p.Name,
+			Description: Description(p.Description),
+			Schema:      SchemaV21,
+		},
+		Variable: []Variable{{Key: "baseUrl", Value: p.BaseURL}},
+	}
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		c.Variable = append(c.Variable, Variable{Key: name, Value: vars[name]})
+	}
+	for _, r := range routes {
+		folders := strings.Split(r.Name, folderSeparator)
+		items := &c.Item
+		for _, folder := range folders[:len(folders)-1] {
+			items = folderItems(items, folder)
+		}
+		*items = append(*items, Item{Name: folders[len(folders)-1], Request: exportRequest(r)})
+	}
+	return c
+}
+
+// folderItems returns the item list of the named folder in items, creating it if needed
+func folderItems(items *[]Item, name string) *[]Item {
+	for i := range *items {
+		if (*items)[i].Name == name && (*items)[i].Request == nil {
+			return &(*items)[i].Item
+		}
+	}
+	*items = append(*items, Item{Name: name})
+	return &(*items)[len(*items)-1].Item
+}
+
+func exportRequest(r *route.Route) *Request {
+	var variables []Variable
+	for _, p := range r.Params {
+		variables = append(variables, Variable{Key: p.Name, Value: p.Default})
+	}
+	path := r.Path
+	for _, p := range r.Params {
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", ":"+p.Name)
+	}
+	rawPath, query, _ := strings.Cut(path, "?")
+	u := URL{
+		Raw:      "{{baseUrl}}" + path,
+		Host:     []string{"{{baseUrl}}"},
+		Path:     strings.Split(strings.Trim(rawPath, "/"), "/"),
+		Variable: variables,
+	}
+	if query != "" {
+		for _, pair := range strings.Split(query, "&") {
+			key, value, _ := strings.Cut(pair, "=")
+			u.Query = append(u.Query, Variable{Key: key, Value: value})
+		}
+	}
+	req := &Request{
+		Method:      string(r.Method),
+		Header:      []Header{},
+		URL:         u,
+		Description: Description(r.Description),
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.Headers)) {
+		req.Header = append(req.Header, Header{Key: name, Value: r.Headers[name]})
+	}
+	if r.ContentType != "" {
+		req.Header = append(req.Header, Header{Key: "Content-Type", Value: r.ContentType})
+	}
+	if r.Body != "" {
+		req.Body = &Body{Mode: "raw", Raw: r.Body}
+		if strings.Contains(r.ContentType, "json") {
+			req.Body.Options = &BodyOptions{}
+			req.Body.Options.Raw.Language = "json"
+		}
+	}
+	return req
+}
@@ -0,0 +1,146 @@
+// Package postman converts between Postman collections (v2.1) and goapi projects
+package postman
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/jsonpath"
+)
+
+// SchemaV21 identifies the collection format written by Export
+const SchemaV21 = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type Info struct {
+	PostmanID   string      `json:"_postman_id,omitempty"`
+	Name        string      `json:"name"`
+	Description Description `json:"description,omitempty"`
+	Schema      string      `json:"schema"`
+}
+
+// Item is either a folder (with Item) or a request
+type Item struct {
+	Name        string      `json:"name"`
+	Description Description `json:"description,omitempty"`
+	Item        []Item      `json:"item,omitempty"`
+	Request     *Request    `json:"request,omitempty"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	Header      []Header    `json:"header"`
+	Body        *Body       `json:"body,omitempty"`
+	URL         URL         `json:"url"`
+	Description Description `json:"description,omitempty"`
+}
+
+type Header struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Body struct {
+	Mode       string       `json:"mode"`
+	Raw        string       `json:"raw,omitempty"`
+	URLEncoded []Variable   `json:"urlencoded,omitempty"`
+	Options    *BodyOptions `json:"options,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+type Variable struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// UnmarshalJSON accepts values of any JSON type, as collections may store numbers
+// and booleans, and keeps them as their JSON text
+func (v *Variable) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Key      string `json:"key"`
+		Value    any    `json:"value"`
+		Disabled bool   `json:"disabled"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return err
+	}
+	*v = Variable{Key: raw.Key, Disabled: raw.Disabled}
+	if raw.Value != nil {
+		v.Value = jsonpath.String(raw.Value)
+	}
+	return nil
+}
+
+// URL is a request URL, which collections store either as a string or as an object
+type URL struct {
+	Raw      string     `json:"raw"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []Variable `json:"query,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = URL{Raw: raw}
+		return nil
+	}
+	type plain URL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+// Description is a description, which collections store either as a string or as an object
+type Description string
+
+func (d *Description) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*d = Description(s)
+		return nil
+	}
+	var obj struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*d = Description(obj.Content)
+	return nil
+}
+
+// Load reads a collection file
+func Load(path string) (*Collection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Collection
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid Postman collection: %w", err)
+	}
+	if c.Info.Name == "" && len(c.Item) == 0 {
+		return nil, fmt.Errorf("invalid Postman collection: no info or items")
+	}
+	if c.Info.Schema != "" && !strings.Contains(c.Info.Schema, "v2.") {
+		return nil, fmt.Errorf("unsupported Postman collection schema: %s", c.Info.Schema)
+	}
+	return &c, nil
+}
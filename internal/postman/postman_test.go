@@ -0,0 +1,165 @@
+package postman
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+const collectionJSON = `{
+  "info": {
+    "name": "Shop",
+    "description": {"content": "Shop API"},
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "variable": [
+    {"key": "baseUrl", "value": "https://shop.example.com/api"},
+    {"key": "token", "value": "abc"},
+    {"key": "retries", "value": 3},
+    {"key": "debug", "value": true}
+  ],
+  "item": [
+    {
+      "name": "Users",
+      "item": [
+        {
+          "name": "Get User",
+          "request": {
+            "method": "GET",
+            "header": [
+              {"key": "authorization", "value": "Bearer {{token}}"},
+              {"key": "X-Debug", "value": "1", "disabled": true}
+            ],
+            "url": {
+              "raw": "{{baseUrl}}/users/:id",
+              "host": ["{{baseUrl}}"],
+              "path": ["users", ":id"],
+              "variable": [{"key": "id", "value": 42}]
+            }
+          }
+        }
+      ]
+    },
+    {
+      "name": "Create Order",
+      "request": {
+        "method": "POST",
+        "header": [],
+        "body": {"mode": "raw", "raw": "{\"sku\": 1}", "options": {"raw": {"language": "json"}}},
+        "url": "{{baseUrl}}/orders"
+      }
+    }
+  ]
+}`
+
+func TestConvert(t *testing.T) {
+	// Arrange
+	var c Collection
+	if err := json.Unmarshal([]byte(collectionJSON), &c); err != nil {
+		t.Fatalf("failed to parse collection: %v", err)
+	}
+
+	// Act
+	imported, err := c.Convert("")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	if imported.Project.Name != "Shop" || imported.Project.BaseURL != "https://shop.example.com/api" {
+		t.Errorf("unexpected project: %+v", imported.Project)
+	}
+	if imported.Variables["token"] != "abc" || imported.Variables["retries"] != "3" || imported.Variables["debug"] != "true" || len(imported.Variables) != 3 {
+		t.Errorf("unexpected variables: %v", imported.Variables)
+	}
+	if len(imported.Routes) != 2 {
+		t.Fatalf("expected 2 routes, got %d", len(imported.Routes))
+	}
+	user := imported.Routes[0]
+	if user.Name != "Users / Get User" || user.Path != "/users/{id}" {
+		t.Errorf("unexpected route: %s %s", user.Name, user.Path)
+	}
+	if len(user.Params) != 1 || user.Params[0].Default != "42" {
+		t.Errorf("unexpected params: %+v", user.Params)
+	}
+	if user.Headers["Authorization"] != "Bearer {{token}}" || len(user.Headers) != 1 {
+		t.Errorf("unexpected headers: %v", user.Headers)
+	}
+	order := imported.Routes[1]
+	if order.Method != route.POST || order.Body != `{"sku": 1}` || order.ContentType != "application/json" {
+		t.Errorf("unexpected route: %+v", order)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	// Arrange
+	var c Collection
+	if err := json.Unmarshal([]byte(collectionJSON), &c); err != nil {
+		t.Fatalf("failed to parse collection: %v", err)
+	}
+	imported, err := c.Convert("")
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+
+	// Act
+	exported := Export(imported.Project, imported.Routes, imported.Variables)
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("failed to encode collection: %v", err)
+	}
+	var decoded Collection
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to decode collection: %v", err)
+	}
+	again, err := decoded.Convert("")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	if len(exported.Item) != 2 || exported.Item[0].Name != "Users" || len(exported.Item[0].Item) != 1 {
+		t.Fatalf("expected folder structure to be rebuilt, got %+v", exported.Item)
+	}
+	if got := exported.Item[0].Item[0].Request.URL.Raw; got != "{{baseUrl}}/users/:id" {
+		t.Errorf("expected Postman path variable, got %s", got)
+	}
+	if again.Project.BaseURL != imported.Project.BaseURL || again.Variables["token"] != "abc" {
+		t.Errorf("round trip changed project or variables: %+v %v", again.Project, again.Variables)
+	}
+	for i, r := range again.Routes {
+		want := imported.Routes[i]
+		if r.Name != want.Name || r.Path != want.Path || r.Body != want.Body || r.ContentType != want.ContentType {
+			t.Errorf("round trip changed route %d: got %+v, want %+v", i, r, want)
+		}
+	}
+}
+
+func TestConvertLiteralURL(t *testing.T) {
+	// Arrange
+	c := Collection{
+		Info: Info{Name: "Plain"},
+		Item: []Item{
+			{Name: "a", Request: &Request{Method: "GET", URL: URL{Raw: "http://localhost:8080/a?x=1"}}},
+			{Name: "b", Request: &Request{Method: "TRACE", URL: URL{Raw: "http://localhost:8080/b"}}},
+		},
+	}
+
+	// Act
+	imported, err := c.Convert("")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("Convert returned error: %v", err)
+	}
+	if imported.Project.BaseURL != "http://localhost:8080" {
+		t.Errorf("expected base URL from request, got %s", imported.Project.BaseURL)
+	}
+	if len(imported.Routes) != 1 || imported.Routes[0].Path != "/a?x=1" {
+		t.Errorf("unexpected routes: %+v", imported.Routes)
+	}
+	if len(imported.Skipped) != 1 {
+		t.Errorf("expected unsupported method to be skipped, got %v", imported.Skipped)
+	}
+}
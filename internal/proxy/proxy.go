@@ -0,0 +1,239 @@
+// Package proxy forwards traffic to an API and records what it sees as routes
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/raworiginal/goapi/internal/mock"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// MaxBodyBytes is the largest request or response body kept in a recorded route
+const MaxBodyBytes = 1 << 20
+
+// credentialHeaders are left out of recorded routes unless KeepAuth is set, since the
+// project's auth profile supplies credentials
+var credentialHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// Exchange is one request and its response as seen by the proxy
+type Exchange struct {
+	Method         string
+	Path           string // request path relative to the target, with the query string
+	RequestHeader  http.Header
+	RequestBody    []byte
+	Status         int
+	ResponseHeader http.Header
+	ResponseBody   []byte
+	Duration       time.Duration
+
+	started time.Time
+	mu      sync.Mutex // guards RequestBody, written while the request streams upstream
+}
+
+type exchangeKey struct{}
+
+// New returns a reverse proxy to target that passes every completed exchange to record.
+// Bodies stream through unchanged; at most MaxBodyBytes+1 bytes of each are kept, and
+// an exchange is recorded once its response body has been read or closed.
+func New(target *url.URL, record func(*Exchange)) *httputil.ReverseProxy {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		ex := &Exchange{
+			Method:        req.Method,
+			Path:          req.URL.RequestURI(),
+			RequestHeader: req.Header.Clone(),
+			started:       time.Now(),
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			req.Body = &capturedBody{body: req.Body, done: func(body []byte) {
+				ex.mu.Lock()
+				defer ex.mu.Unlock()
+				ex.RequestBody = body
+			}}
+		}
+		*req = *req.WithContext(context.WithValue(req.Context(), exchangeKey{}, ex))
+		director(req)
+		req.Host = target.Host
+	}
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		ex, ok := resp.Request.Context().Value(exchangeKey{}).(*Exchange)
+		if !ok {
+			return nil
+		}
+		ex.Status = resp.StatusCode
+		ex.ResponseHeader = resp.Header.Clone()
+		encoding := resp.Header.Get("Content-Encoding")
+		resp.Body = &capturedBody{body: resp.Body, done: func(body []byte) {
+			ex.mu.Lock()
+			ex.ResponseBody = decodeBody(body, encoding)
+			ex.Duration = time.Since(ex.started)
+			ex.mu.Unlock()
+			record(ex)
+		}}
+		return nil
+	}
+	return proxy
+}
+
+// capturedBody passes a body through while keeping up to MaxBodyBytes+1 bytes of it,
+// enough to tell whether it was too large to record. done is called once, when the
+// body has been read to the end, fails, or is closed.
+type capturedBody struct {
+	body io.ReadCloser
+	kept bytes.Buffer
+	done func(kept []byte)
+	once sync.Once
+}
+
+func (c *capturedBody) Read(p []byte) (int, error) {
+	n, err := c.body.Read(p)
+	if room := MaxBodyBytes + 1 - c.kept.Len(); room > 0 {
+		c.kept.Write(p[:min(n, room)])
+	}
+	if err != nil {
+		c.finish()
+	}
+	return n, err
+}
+
+func (c *capturedBody) Close() error {
+	err := c.body.Close()
+	c.finish()
+	return err
+}
+
+func (c *capturedBody) finish() {
+	c.once.Do(func() { c.done(c.kept.Bytes()) })
+}
+
+// Recorder creates and updates a project's routes from exchanges
+type Recorder struct {
+	ProjectID uint
+	KeepAuth  bool // keep credential headers in recorded routes
+	Create    func(*route.Route) error
+	Update    func(id uint, updates *route.UpdateRouteInput) error
+
+	mu      sync.Mutex
+	routes  []*route.Route
+	matcher *mock.Server
+}
+
+// NewRecorder creates a recorder for a project's existing routes
+func NewRecorder(projectID uint, routes []*route.Route) *Recorder {
+	rec := &Recorder{ProjectID: projectID, routes: routes}
+	rec.matcher = mock.New("", routes, nil)
+	return rec
+}
+
+// Record stores ex as the example of the route matching its method and path, creating
+// the route if there is none. It returns the route and whether it was created.
+func (rec *Recorder) Record(ex *Exchange) (*route.Route, bool, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	path, _, _ := strings.Cut(ex.Path, "?")
+	captured, err := rec.capture(ex)
+	if err != nil {
+		return nil, false, err
+	}
+	existing, _ := rec.matcher.Match(ex.Method, path)
+	if existing == nil {
+		captured.ProjectID = rec.ProjectID
+		captured.Name = rec.uniqueName(captured.Method, path)
+		if err := rec.Create(captured); err != nil {
+			return nil, false, err
+		}
+		rec.routes = append(rec.routes, captured)
+		rec.matcher = mock.New("", rec.routes, nil)
+		return captured, true, nil
+	}
+
+	// templated routes keep their path; literal ones follow the latest query string
+	updates := &route.UpdateRouteInput{
+		Headers:     &captured.Headers,
+		ContentType: &captured.ContentType,
+		Body:        &captured.Body,
+		Example:     &captured.Example,
+	}
+	if !strings.Contains(existing.Path, "{") {
+		updates.Path = &captured.Path
+	}
+	if err := rec.Update(existing.ID, updates); err != nil {
+		return nil, false, err
+	}
+	existing.Headers, existing.ContentType, existing.Body, existing.Example = captured.Headers, captured.ContentType, captured.Body, captured.Example
+	if updates.Path != nil {
+		existing.Path = captured.Path
+	}
+	return existing, false, nil
+}
+
+// capture converts ex into an unsaved route
+func (rec *Recorder) capture(ex *Exchange) (*route.Route, error) {
+	method, err := route.ParseHTTPMethod(ex.Method)
+	if err != nil {
+		return nil, err
+	}
+	r := &route.Route{Method: method, Path: ex.Path, Headers: make(route.Headers)}
+	for name, values := range ex.RequestHeader {
+		if !rec.KeepAuth && slices.Contains(credentialHeaders, http.CanonicalHeaderKey(name)) {
+			continue
+		}
+		r.SetCapturedHeader(name, strings.Join(values, ", "))
+	}
+	ex.mu.Lock()
+	r.Body = textBody(ex.RequestBody)
+	ex.mu.Unlock()
+	r.Example = route.Example{Status: ex.Status, Body: textBody(ex.ResponseBody)}
+	if contentType := ex.ResponseHeader.Get("Content-Type"); contentType != "" {
+		r.Example.Headers = map[string]string{"Content-Type": contentType}
+	}
+	return r, nil
+}
+
+// uniqueName returns "METHOD /path", with a counter when the name is taken
+func (rec *Recorder) uniqueName(method route.HTTPMethod, path string) string {
+	base := string(method) + " " + path
+	name := base
+	for n := 2; slices.ContainsFunc(rec.routes, func(r *route.Route) bool { return r.Name == name }); n++ {
+		name = fmt.Sprintf("%s (%d)", base, n)
+	}
+	return name
+}
+
+// textBody returns body as a string, or empty when it is binary or too large to keep
+func textBody(body []byte) string {
+	if len(body) > MaxBodyBytes || !utf8.Valid(body) {
+		return ""
+	}
+	return string(body)
+}
+
+// decodeBody returns the uncompressed body of gzip responses, leaving others unchanged
+func decodeBody(body []byte, encoding string) []byte {
+	if encoding != "gzip" {
+		return body
+	}
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return body
+	}
+	decoded, err := io.ReadAll(io.LimitReader(r, MaxBodyBytes+1))
+	if err != nil {
+		return body
+	}
+	return decoded
+}
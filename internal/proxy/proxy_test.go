@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+func TestProxyRecordsExchange(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","body":` + string(body) + `}`))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/v1")
+
+	recorded := make(chan *Exchange, 1)
+	server := httptest.NewServer(New(target, func(ex *Exchange) { recorded <- ex }))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/users?x=1", "application/json", strings.NewReader(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if string(body) != `{"path":"/v1/users","body":{"a":1}}` {
+		t.Errorf("expected upstream body to reach client, got %s", body)
+	}
+	got := <-recorded
+	if got.Method != "POST" || got.Path != "/users?x=1" || got.Status != 201 {
+		t.Errorf("unexpected exchange: %s %s -> %d", got.Method, got.Path, got.Status)
+	}
+	if string(got.RequestBody) != `{"a":1}` || string(got.ResponseBody) != string(body) {
+		t.Errorf("unexpected bodies: %s / %s", got.RequestBody, got.ResponseBody)
+	}
+}
+
+func TestProxyStreamsResponses(t *testing.T) {
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte(strings.Repeat("x", MaxBodyBytes)))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+
+	recorded := make(chan *Exchange, 1)
+	server := httptest.NewServer(New(target, func(ex *Exchange) { recorded <- ex }))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	// the first event arrives while the upstream is still writing
+	first := make([]byte, len("data: first\n\n"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil || string(first) != "data: first\n\n" {
+		t.Fatalf("expected first event before the body ends, got %q (%v)", first, err)
+	}
+	close(release)
+	rest, _ := io.ReadAll(resp.Body)
+	if len(rest) != MaxBodyBytes {
+		t.Errorf("expected the whole body to reach the client, got %d bytes", len(rest))
+	}
+
+	ex := <-recorded
+	if len(ex.ResponseBody) != MaxBodyBytes+1 {
+		t.Errorf("expected recording to stop at %d bytes, got %d", MaxBodyBytes+1, len(ex.ResponseBody))
+	}
+}
+
+func TestRecorderCreatesAndUpdates(t *testing.T) {
+	existing := &route.Route{ID: 1, Name: "Get user", Method: route.GET, Path: "/users/{id}"}
+	taken := &route.Route{ID: 2, Name: "GET /items", Method: route.GET, Path: "/old/items"}
+	rec := NewRecorder(7, []*route.Route{existing, taken})
+	var created []*route.Route
+	updated := map[uint]*route.UpdateRouteInput{}
+	rec.Create = func(r *route.Route) error {
+		r.ID = uint(10 + len(created))
+		created = append(created, r)
+		return nil
+	}
+	rec.Update = func(id uint, u *route.UpdateRouteInput) error {
+		updated[id] = u
+		return nil
+	}
+
+	ex := &Exchange{
+		Method:         "GET",
+		Path:           "/users/42",
+		RequestHeader:  http.Header{"Authorization": {"Bearer secret"}, "X-Trace": {"abc"}},
+		Status:         200,
+		ResponseHeader: http.Header{"Content-Type": {"application/json"}},
+		ResponseBody:   []byte(`{"id":42}`),
+	}
+	r, isNew, err := rec.Record(ex)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if isNew || r != existing || updated[1] == nil {
+		t.Fatalf("expected templated route to be updated, got %+v", r)
+	}
+	if updated[1].Path != nil {
+		t.Errorf("expected templated path to be kept, got %s", *updated[1].Path)
+	}
+	if _, ok := existing.Headers["Authorization"]; ok || existing.Headers["X-Trace"] != "abc" {
+		t.Errorf("unexpected recorded headers: %v", existing.Headers)
+	}
+	if existing.Example.Status != 200 || existing.Example.Body != `{"id":42}` {
+		t.Errorf("unexpected example: %+v", existing.Example)
+	}
+
+	r, isNew, err = rec.Record(&Exchange{Method: "GET", Path: "/items?page=2", Status: 200})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !isNew || r.Name != "GET /items (2)" || r.Path != "/items?page=2" || r.ProjectID != 7 {
+		t.Errorf("unexpected created route: %+v", r)
+	}
+	if _, isNew, _ = rec.Record(&Exchange{Method: "GET", Path: "/items", Status: 200}); isNew {
+		t.Error("expected second request to the same path to update the new route")
+	}
+	if len(created) != 1 {
+		t.Errorf("expected 1 created route, got %d", len(created))
+	}
+}
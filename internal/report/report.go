@@ -0,0 +1,122 @@
+// Package report writes test results in table, JSON, NDJSON and JUnit XML formats
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/runner"
+)
+
+type Format string
+
+const (
+	Table  Format = "table"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+	JUnit  Format = "junit"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "table":
+		return Table, nil
+	case "json":
+		return JSON, nil
+	case "ndjson":
+		return NDJSON, nil
+	case "junit":
+		return JUnit, nil
+	default:
+		return "", fmt.Errorf("invalid output format: %s. Valid formats are: table, json, ndjson, junit", s)
+	}
+}
+
+// Report is a complete test run
+type Report struct {
+	Project     string              `json:"project"`
+	Environment string              `json:"environment,omitempty"`
+	StartedAt   time.Time           `json:"started_at"`
+	Duration    time.Duration       `json:"duration_ns"`
+	Passed      int                 `json:"passed"`
+	Failed      int                 `json:"failed"`
+	Results     []runner.TestResult `json:"results"`
+}
+
+// New builds a report and counts its passed and failed results
+func New(project, environment string, startedAt time.Time, results []runner.TestResult) *Report {
+	r := &Report{
+		Project:     project,
+		Environment: environment,
+		StartedAt:   startedAt,
+		Duration:    time.Since(startedAt),
+		Results:     results,
+	}
+	for _, result := range results {
+		if result.Passed() {
+			r.Passed++
+		} else {
+			r.Failed++
+		}
+	}
+	return r
+}
+
+// Write renders the report to w in the given format
+func (r *Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		for _, result := range r.Results {
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	case JUnit:
+		return r.writeJUnit(w)
+	default:
+		return WriteTable(w, r.Results)
+	}
+}
+
+// WriteTable prints results as a table followed by the details of any failures
+func WriteTable(out io.Writer, results []runner.TestResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "ID\tName\tMethod\tPath\tStatus\tDuration\tResult"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+
+	for _, r := range results {
+		status := "Error"
+		if r.Error == "" {
+			status = fmt.Sprintf("%d", r.StatusCode)
+		}
+		outcome := "PASS"
+		if !r.Passed() {
+			outcome = "FAIL"
+		}
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%v\t%s\n", r.RouteID, r.RouteName, r.Method, r.Path, status, r.Duration, outcome); err != nil {
+			return fmt.Errorf("failed to write table line: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush table: %w", err)
+	}
+	for _, r := range results {
+		for _, line := range r.Failures() {
+			if _, err := fmt.Fprintf(out, "%s: %s\n", r.RouteName, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/runner"
+)
+
+func sampleReport() *Report {
+	return New("MyAPI", "staging", time.Now(), []runner.TestResult{
+		{RouteID: 1, RouteName: "List Users", Method: "GET", Path: "/users", StatusCode: 200},
+		{RouteID: 2, RouteName: "Get User", Method: "GET", Path: "/users/1", StatusCode: 404,
+			Assertions: []assert.Result{{Name: "status 2xx", Message: "got status 404"}}},
+		{RouteID: 3, RouteName: "Slow", Method: "GET", Path: "/slow", Error: "context deadline exceeded"},
+	})
+}
+
+func TestNewCounts(t *testing.T) {
+	r := sampleReport()
+	if r.Passed != 1 || r.Failed != 2 {
+		t.Errorf("expected 1 passed and 2 failed, got %d and %d", r.Passed, r.Failed)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, JUnit); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("expected valid XML, got %v", err)
+	}
+	if doc.Tests != 3 || doc.Failures != 1 || doc.Errors != 1 {
+		t.Errorf("expected 3 tests, 1 failure, 1 error, got %d, %d, %d", doc.Tests, doc.Failures, doc.Errors)
+	}
+	if c := doc.Suites[0].Cases[1]; c.Failure == nil || !strings.Contains(c.Failure.Message, "404") {
+		t.Errorf("expected failure message for Get User, got %+v", c.Failure)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, NDJSON); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("expected 3 lines, got %d", lines)
+	}
+}
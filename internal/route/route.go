@@ -3,8 +3,13 @@ package route
 
 import (
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/extract"
 )
 
 type HTTPMethod string
@@ -18,20 +23,88 @@ const (
 )
 
 type Route struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	ProjectID   uint       `gorm:"foreignKey; uniqueIndex:idx_project_route_name" json:"project_id"`
-	Name        string     `gorm:"uniqueIndex:idx_project_route_name" json:"name"`
-	Method      HTTPMethod `json:"method"`
-	Path        string     `json:"path"`
-	Description string     `json:"description"`
-	DateCreated time.Time  `gorm:"autoCreateTime" json:"date_created"`
+	ID             uint                 `gorm:"primaryKey" json:"id"`
+	ProjectID      uint                 `gorm:"foreignKey; uniqueIndex:idx_project_route_name" json:"project_id"`
+	Name           string               `gorm:"uniqueIndex:idx_project_route_name" json:"name"`
+	Method         HTTPMethod           `json:"method"`
+	Path           string               `json:"path"`
+	Params         []Param              `gorm:"serializer:json" json:"params"`
+	Headers        Headers              `gorm:"serializer:json" json:"headers"`
+	ContentType    string               `json:"content_type"`
+	Body           string               `json:"body"`
+	Public         bool                 `json:"public"` // sent without the project's auth
+	Expect         assert.Expectation   `gorm:"serializer:json" json:"expect"`
+	Extract        []extract.Extraction `gorm:"serializer:json" json:"extract"`                   // variables captured from the response
+	Order          int                  `gorm:"column:run_order" json:"order"`                    // position when the project's routes run
+	Example        Example              `gorm:"serializer:json" json:"example"`                   // response served by the mock server
+	ResponseSchema string               `json:"response_schema,omitempty"`                        // JSON Schema for the response body: inline, a file, or file#pointer
+	SnapshotIgnore []string             `gorm:"serializer:json" json:"snapshot_ignore,omitempty"` // JSON paths left out of snapshot comparison
+	Description    string               `json:"description"`
+	DateCreated    time.Time            `gorm:"autoCreateTime" json:"date_created"`
 }
 
 type UpdateRouteInput struct {
-	Name        *string     `json:"name,omitempty"`
-	Method      *HTTPMethod `json:"method,omitempty"`
-	Path        *string     `json:"path,omitempty"`
-	Description *string     `json:"description,omitempty"`
+	Name           *string               `json:"name,omitempty"`
+	Method         *HTTPMethod           `json:"method,omitempty"`
+	Path           *string               `json:"path,omitempty"`
+	Params         *[]Param              `gorm:"serializer:json" json:"params,omitempty"`
+	Headers        *Headers              `gorm:"serializer:json" json:"headers,omitempty"`
+	ContentType    *string               `json:"content_type,omitempty"`
+	Body           *string               `json:"body,omitempty"`
+	Public         *bool                 `json:"public,omitempty"`
+	Expect         *assert.Expectation   `gorm:"serializer:json" json:"expect,omitempty"`
+	Extract        *[]extract.Extraction `gorm:"serializer:json" json:"extract,omitempty"`
+	Order          *int                  `gorm:"column:run_order" json:"order,omitempty"`
+	Example        *Example              `gorm:"serializer:json" json:"example,omitempty"`
+	ResponseSchema *string               `json:"response_schema,omitempty"`
+	SnapshotIgnore *[]string             `gorm:"serializer:json" json:"snapshot_ignore,omitempty"`
+	Description    *string               `json:"description,omitempty"`
+}
+
+// Headers maps request header names to values
+type Headers map[string]string
+
+// Example is a sample response for a route
+type Example struct {
+	Status  int               `json:"status,omitempty"` // 0 for the route's expected status, or 200
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// IsZero reports whether no example is set
+func (e *Example) IsZero() bool {
+	return e.Status == 0 && len(e.Headers) == 0 && e.Body == ""
+}
+
+// transportHeaders are set by the HTTP client itself or only make sense for one connection
+var transportHeaders = []string{"Host", "Content-Length", "Connection", "Accept-Encoding", "Keep-Alive", "Transfer-Encoding", "Proxy-Connection", "Upgrade"}
+
+// SetCapturedHeader records a request header seen on the wire. Content-Type becomes the
+// route's content type; pseudo headers and transport-level headers are dropped.
+func (r *Route) SetCapturedHeader(name, value string) {
+	key := http.CanonicalHeaderKey(name)
+	switch {
+	case strings.HasPrefix(name, ":"), slices.Contains(transportHeaders, key):
+	case key == "Content-Type":
+		r.ContentType = value
+	default:
+		if r.Headers == nil {
+			r.Headers = make(Headers)
+		}
+		r.Headers[key] = value
+	}
+}
+
+// RequestHeaders returns the headers to send for the route, including its content type
+func (r *Route) RequestHeaders() map[string]string {
+	headers := make(map[string]string, len(r.Headers)+1)
+	for name, value := range r.Headers {
+		headers[name] = value
+	}
+	if r.ContentType != "" {
+		headers["Content-Type"] = r.ContentType
+	}
+	return headers
 }
 
 func ParseHTTPMethod(s string) (HTTPMethod, error) {
@@ -0,0 +1,173 @@
+package route
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Param is a path parameter declared by a `{name}` placeholder in a route path
+type Param struct {
+	Name    string `json:"name"`
+	Default string `json:"default,omitempty"`
+}
+
+// ParsePathParams returns the placeholder names declared in path, in order of appearance.
+// `{{var}}` variable references are not parameters and are skipped.
+func ParsePathParams(path string) ([]string, error) {
+	var names []string
+	_, err := expandParams(path, func(name string) (string, error) {
+		if slices.Contains(names, name) {
+			return "", fmt.Errorf("duplicate path parameter '%s' in path: %s", name, path)
+		}
+		names = append(names, name)
+		return "", nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// BuildParams parses path and attaches the given default values to its parameters
+func BuildParams(path string, defaults map[string]string) ([]Param, error) {
+	names, err := ParsePathParams(path)
+	if err != nil {
+		return nil, err
+	}
+	for name := range defaults {
+		if !slices.Contains(names, name) {
+			return nil, fmt.Errorf("path %s has no parameter named '%s'", path, name)
+		}
+	}
+	params := make([]Param, 0, len(names))
+	for _, name := range names {
+		params = append(params, Param{Name: name, Default: defaults[name]})
+	}
+	return params, nil
+}
+
+// RebuildParams declares the parameters of a new path, keeping the existing
+// defaults of parameters that are still present and applying overrides
+func RebuildParams(existing []Param, path string, overrides map[string]string) ([]Param, error) {
+	names, err := ParsePathParams(path)
+	if err != nil {
+		return nil, err
+	}
+	defaults := make(map[string]string)
+	for _, p := range existing {
+		if slices.Contains(names, p.Name) && p.Default != "" {
+			defaults[p.Name] = p.Default
+		}
+	}
+	maps.Copy(defaults, overrides)
+	return BuildParams(path, defaults)
+}
+
+// ResolvePath substitutes the route's path parameters, preferring values over stored defaults
+func (r *Route) ResolvePath(values map[string]string) (string, error) {
+	defaults := make(map[string]string, len(r.Params))
+	for _, p := range r.Params {
+		defaults[p.Name] = p.Default
+	}
+
+	var missing []string
+	resolved, err := expandParams(r.Path, func(name string) (string, error) {
+		value, ok := values[name]
+		if !ok || value == "" {
+			value = defaults[name]
+		}
+		if value == "" {
+			missing = append(missing, name)
+		}
+		return url.PathEscape(value), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("route '%s' is missing values for path parameters: %s (use --param name=value)", r.Name, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// expandParams replaces each `{name}` placeholder in path with the result of fn
+func expandParams(path string, fn func(name string) (string, error)) (string, error) {
+	var b strings.Builder
+	rest := path
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open == -1 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:open])
+		rest = rest[open:]
+		if strings.HasPrefix(rest, "{{") {
+			end := strings.Index(rest, "}}")
+			if end == -1 {
+				return "", fmt.Errorf("unclosed '{{' in path: %s", path)
+			}
+			b.WriteString(rest[:end+2])
+			rest = rest[end+2:]
+			continue
+		}
+		if rest[0] == '}' {
+			return "", fmt.Errorf("unexpected '}' in path: %s", path)
+		}
+		end := strings.IndexAny(rest[1:], "{}")
+		if end == -1 || rest[1+end] == '{' {
+			return "", fmt.Errorf("unclosed '{' in path: %s", path)
+		}
+		name := rest[1 : 1+end]
+		if !validParamName(name) {
+			return "", fmt.Errorf("invalid path parameter name '%s' in path: %s", name, path)
+		}
+		value, err := fn(name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		rest = rest[1+end+1:]
+	}
+	return b.String(), nil
+}
+
+var varPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Interpolate replaces `{{name}}` references in s with values from vars
+func Interpolate(s string, vars map[string]string) (string, error) {
+	var missing []string
+	result := varPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := varPattern.FindStringSubmatch(ref)[1]
+		value, ok := vars[name]
+		if !ok {
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+			return ref
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined variables: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+func validParamName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '-', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
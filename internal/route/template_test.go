@@ -0,0 +1,88 @@
+package route
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePathParams(t *testing.T) {
+	names, err := ParsePathParams("/users/{id}/posts/{post_id}")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(names) != 2 || names[0] != "id" || names[1] != "post_id" {
+		t.Errorf("expected [id post_id], got %v", names)
+	}
+}
+
+func TestParsePathParamsInvalid(t *testing.T) {
+	for _, path := range []string{"/users/{id", "/users/id}", "/users/{}", "/users/{id}/{id}", "/users/{a b}"} {
+		if _, err := ParsePathParams(path); err == nil {
+			t.Errorf("expected error for path %s", path)
+		}
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	// Arrange: a route with one defaulted and one required parameter
+	r := &Route{
+		Name:   "Get Post",
+		Path:   "/users/{id}/posts/{post_id}",
+		Params: []Param{{Name: "id", Default: "1"}, {Name: "post_id"}},
+	}
+
+	// Act: resolve with only the required value
+	path, err := r.ResolvePath(map[string]string{"post_id": "a b"})
+
+	// Assert: defaults fill in and values are escaped
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if path != "/users/1/posts/a%20b" {
+		t.Errorf("expected /users/1/posts/a%%20b, got %s", path)
+	}
+}
+
+func TestResolvePathMissing(t *testing.T) {
+	r := &Route{Name: "Get User", Path: "/users/{id}", Params: []Param{{Name: "id"}}}
+
+	_, err := r.ResolvePath(nil)
+	if err == nil {
+		t.Fatal("expected error for unbound parameter, got nil")
+	}
+	if !strings.Contains(err.Error(), "id") {
+		t.Errorf("expected error to name the parameter, got: %v", err)
+	}
+}
+
+func TestBuildParamsUnknownDefault(t *testing.T) {
+	if _, err := BuildParams("/users/{id}", map[string]string{"name": "x"}); err == nil {
+		t.Fatal("expected error for undeclared parameter default, got nil")
+	}
+}
+
+func TestResolvePathKeepsVariables(t *testing.T) {
+	r := &Route{Name: "Get Item", Path: "/{{version}}/items/{id}", Params: []Param{{Name: "id"}}}
+
+	path, err := r.ResolvePath(map[string]string{"id": "3"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if path != "/{{version}}/items/3" {
+		t.Errorf("expected /{{version}}/items/3, got %s", path)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	got, err := Interpolate(`{"user": "{{ user }}", "env": "{{env}}"}`, map[string]string{"user": "ada", "env": "staging"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != `{"user": "ada", "env": "staging"}` {
+		t.Errorf("unexpected interpolation result: %s", got)
+	}
+
+	if _, err := Interpolate("/{{missing}}", nil); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected undefined variable error, got %v", err)
+	}
+}
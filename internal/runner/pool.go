@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// ErrFailFast is the cancellation cause for requests aborted by an earlier failure
+var ErrFailFast = errors.New("an earlier route failed")
+
+// RunAll executes routes with up to concurrency requests in flight and returns the
+// results in the order of routes. With failFast, the first failure cancels requests
+// still in flight, and routes that had not started are left out of the results.
+// Routes with extractions finish before any later route starts, so later routes
+// can use the captured variables.
+func (run *Runner) RunAll(ctx context.Context, routes []*route.Route, concurrency int, failFast bool) []TestResult {
+	concurrency = max(1, min(concurrency, len(routes)))
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]TestResult, len(routes))
+	started := make([]bool, len(routes))
+	done := make([]chan struct{}, len(routes))
+	for i, r := range routes {
+		if len(r.Extract) > 0 {
+			done[i] = make(chan struct{})
+		}
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Go(func() {
+			for i := range jobs {
+				if ctx.Err() == nil {
+					started[i] = true
+					results[i] = run.RunContext(ctx, routes[i])
+					if failFast && !results[i].Passed() {
+						cancel(ErrFailFast)
+					}
+				}
+				if done[i] != nil {
+					close(done[i])
+				}
+			}
+		})
+	}
+	for i := range routes {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+		if done[i] != nil {
+			<-done[i]
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	ran := results[:0]
+	for i, result := range results {
+		if started[i] {
+			ran = append(ran, result)
+		}
+	}
+	return ran
+}
@@ -0,0 +1,155 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/extract"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// newDelayServer answers /delay/{ms} after sleeping and /fail with a 500,
+// tracking the highest number of concurrent requests
+func newDelayServer(t *testing.T, peak *int32) *httptest.Server {
+	var inFlight int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /delay/{ms}", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			old := atomic.LoadInt32(peak)
+			if n <= old || atomic.CompareAndSwapInt32(peak, old, n) {
+				break
+			}
+		}
+		ms, _ := strconv.Atoi(r.PathValue("ms"))
+		select {
+		case <-time.After(time.Duration(ms) * time.Millisecond):
+		case <-r.Context().Done():
+		}
+	})
+	mux.HandleFunc("GET /fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func delayRoute(name string, path string) *route.Route {
+	return &route.Route{Name: name, Method: route.GET, Path: path, Expect: assert.Expectation{Status: "200"}}
+}
+
+func TestRunAllKeepsOrder(t *testing.T) {
+	// Arrange: later routes answer first
+	var peak int32
+	server := newDelayServer(t, &peak)
+	run, err := New(&project.Project{BaseURL: server.URL}, api.Config{Timeout: 5 * time.Second}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var routes []*route.Route
+	for i, ms := range []int{80, 60, 40, 20, 0, 0} {
+		routes = append(routes, delayRoute(strconv.Itoa(i), "/delay/"+strconv.Itoa(ms)))
+	}
+
+	// Act
+	results := run.RunAll(context.Background(), routes, 3, false)
+
+	// Assert
+	if len(results) != len(routes) {
+		t.Fatalf("expected %d results, got %d", len(routes), len(results))
+	}
+	for i, result := range results {
+		if result.RouteName != strconv.Itoa(i) || !result.Passed() {
+			t.Errorf("result %d: got route %s, passed=%v", i, result.RouteName, result.Passed())
+		}
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 concurrent requests, got %d", peak)
+	}
+}
+
+func TestRunAllFailFast(t *testing.T) {
+	// Arrange: a slow route is in flight when another one fails
+	var peak int32
+	server := newDelayServer(t, &peak)
+	run, err := New(&project.Project{BaseURL: server.URL}, api.Config{Timeout: 5 * time.Second}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	routes := []*route.Route{
+		delayRoute("slow", "/delay/5000"),
+		delayRoute("fail", "/fail"),
+		delayRoute("never", "/delay/0"),
+	}
+
+	// Act
+	start := time.Now()
+	results := run.RunAll(context.Background(), routes, 2, true)
+
+	// Assert
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the slow request to be cancelled, took %v", elapsed)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].RouteName != "slow" || !strings.Contains(results[0].Error, ErrFailFast.Error()) {
+		t.Errorf("expected slow route to be cancelled, got %+v", results[0])
+	}
+	if results[1].RouteName != "fail" || results[1].StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected failing route second, got %+v", results[1])
+	}
+}
+
+func TestRunAllChainsExtractions(t *testing.T) {
+	// Arrange: the create route is slow, so dependents would race ahead without ordering
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"user": {"id": 42}}`))
+	})
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "42" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	run, err := New(&project.Project{BaseURL: server.URL}, api.Config{Timeout: 5 * time.Second}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	create := &route.Route{Name: "create", Method: route.POST, Path: "/users", Extract: []extract.Extraction{
+		{Name: "user_id", Source: extract.Body, Expr: "$.user.id"},
+		{Name: "missing", Source: extract.Body, Expr: "$.nope"},
+	}}
+	routes := []*route.Route{create, delayRoute("get1", "/users/{{user_id}}"), delayRoute("get2", "/users/{{user_id}}")}
+
+	// Act
+	results := run.RunAll(context.Background(), routes, 3, false)
+
+	// Assert
+	if results[0].Extracted["user_id"] != "42" {
+		t.Errorf("expected user_id to be extracted, got %v", results[0].Extracted)
+	}
+	if results[0].Passed() || !strings.Contains(strings.Join(results[0].Failures(), "\n"), "extract missing") {
+		t.Errorf("expected failed extraction to be reported, got %v", results[0].Failures())
+	}
+	for _, result := range results[1:] {
+		if !result.Passed() || result.Path != "/users/42" {
+			t.Errorf("expected %s to use the extracted id, got %s %v", result.RouteName, result.Path, result.Failures())
+		}
+	}
+}
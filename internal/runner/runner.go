@@ -0,0 +1,332 @@
+// Package runner executes a project's routes against its API
+package runner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/schema"
+)
+
+type TestResult struct {
+	RouteID         uint              `json:"route_id"`
+	RouteName       string            `json:"route_name"`
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	StatusCode      int               `json:"status_code"`
+	Duration        time.Duration     `json:"duration_ns"`
+	Error           string            `json:"error,omitempty"`
+	Assertions      []assert.Result   `json:"assertions,omitempty"`
+	ResponseHeaders http.Header       `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	Extracted       map[string]string `json:"extracted,omitempty"` // variables captured from the response
+}
+
+// Passed reports whether the request succeeded and all assertions held
+func (r TestResult) Passed() bool {
+	return r.Error == "" && assert.Passed(r.Assertions)
+}
+
+// Failures describes why the result failed, one line per problem
+func (r TestResult) Failures() []string {
+	var lines []string
+	if r.Error != "" {
+		lines = append(lines, r.Error)
+	}
+	for _, a := range r.Assertions {
+		if !a.Passed {
+			lines = append(lines, a.Name+": "+a.Message)
+		}
+	}
+	return lines
+}
+
+// Check inspects a route's response and returns results for any checks it made
+type Check func(r *route.Route, resp *api.Response) []assert.Result
+
+type Runner struct {
+	Project   *project.Project
+	Client    api.Client
+	BaseURL   string            // the project's base URL unless an environment overrides it
+	Params    map[string]string // path parameter values overriding route defaults
+	Vars      map[string]string // values for {{name}} references, extended by route extractions
+	Checks    []Check           // run on every response after the route's expectations
+	varsMu    sync.RWMutex
+	schemas   map[string]*schema.Schema // response schemas loaded so far, by reference
+	schemasMu sync.Mutex
+	auth      api.Authenticator
+	session   *auth.Session
+}
+
+// New creates a runner for p. For JWT profiles, login is the route that issues
+// tokens and save persists refreshed tokens.
+func New(p *project.Project, config api.Config, profile *auth.Profile, login *route.Route, save func(*auth.Profile) error) (*Runner, error) {
+	run := &Runner{Project: p, BaseURL: p.BaseURL, Vars: make(map[string]string)}
+	if profile != nil {
+		if profile.Type == auth.JWT {
+			if login == nil {
+				return nil, fmt.Errorf("jwt auth requires a login route")
+			}
+			run.session = &auth.Session{
+				Profile: profile,
+				Login:   func() (string, error) { return run.Login(login, profile.TokenPath) },
+				Save:    save,
+			}
+			config.Auth = run.session
+		} else {
+			config.Auth = profile
+		}
+	}
+	run.auth = config.Auth
+	run.Client = api.NewHTTPClient(config)
+	return run, nil
+}
+
+// UseEnvironment applies an environment's base URL and variables to the runner
+func (run *Runner) UseEnvironment(e *environment.Environment) {
+	if e.BaseURL != "" {
+		run.BaseURL = e.BaseURL
+	}
+	maps.Copy(run.Vars, e.Variables)
+}
+
+// Request builds the HTTP request for r, resolving path parameters and variables
+func (run *Runner) Request(r *route.Route) (*api.Request, error) {
+	req, _, err := run.build(r)
+	return req, err
+}
+
+// HTTPRequest builds r as a standard library request with the project's auth applied.
+// JWT auth uses the cached token, or auth.TokenVariable, and never logs in.
+func (run *Runner) HTTPRequest(r *route.Route) (*http.Request, error) {
+	req, err := run.Request(r)
+	if err != nil {
+		return nil, err
+	}
+	authenticator := run.auth
+	if run.session != nil {
+		authenticator = run.session.Cached()
+	}
+	return req.HTTPRequest(context.Background(), authenticator)
+}
+
+// vars returns a snapshot of the variables, safe to use while other routes run
+func (run *Runner) vars() map[string]string {
+	run.varsMu.RLock()
+	defer run.varsMu.RUnlock()
+	return maps.Clone(run.Vars)
+}
+
+func (run *Runner) build(r *route.Route) (*api.Request, string, error) {
+	vars := run.vars()
+	values := make(map[string]string, len(r.Params)+len(run.Params))
+	for _, p := range r.Params {
+		values[p.Name] = p.Default
+	}
+	maps.Copy(values, run.Params)
+	for name, value := range values {
+		interpolated, err := route.Interpolate(value, vars)
+		if err != nil {
+			return nil, "", fmt.Errorf("path parameter '%s': %w", name, err)
+		}
+		values[name] = interpolated
+	}
+	path, err := r.ResolvePath(values)
+	if err != nil {
+		return nil, "", err
+	}
+	if path, err = route.Interpolate(path, vars); err != nil {
+		return nil, "", fmt.Errorf("path: %w", err)
+	}
+	baseURL, err := route.Interpolate(run.BaseURL, vars)
+	if err != nil {
+		return nil, "", fmt.Errorf("base URL: %w", err)
+	}
+	headers := r.RequestHeaders()
+	for name, value := range headers {
+		if headers[name], err = route.Interpolate(value, vars); err != nil {
+			return nil, "", fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+	body, err := route.Interpolate(r.Body, vars)
+	if err != nil {
+		return nil, "", fmt.Errorf("body: %w", err)
+	}
+	return &api.Request{
+		Method:  string(r.Method),
+		URL:     baseURL + path,
+		Headers: headers,
+		Body:    []byte(body),
+		NoAuth:  r.Public,
+	}, path, nil
+}
+
+// Send executes r, logging in again and retrying once if a JWT is rejected
+func (run *Runner) Send(r *route.Route) (*api.Response, error) {
+	req, err := run.Request(r)
+	if err != nil {
+		return nil, err
+	}
+	return run.send(context.Background(), req)
+}
+
+// SendRequest sends a request built by Request, with the same JWT retry as Send
+func (run *Runner) SendRequest(ctx context.Context, req *api.Request) (*api.Response, error) {
+	return run.send(ctx, req)
+}
+
+func (run *Runner) send(ctx context.Context, req *api.Request) (*api.Response, error) {
+	if run.session == nil || req.NoAuth {
+		return run.Client.SendContext(ctx, req)
+	}
+
+	token, err := run.session.Token()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := run.Client.SendContext(ctx, req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		run.session.Invalidate(token)
+		resp, err = run.Client.SendContext(ctx, req)
+	}
+	return resp, err
+}
+
+// Run executes r and records the outcome
+func (run *Runner) Run(r *route.Route) TestResult {
+	return run.RunContext(context.Background(), r)
+}
+
+// RunContext executes r, aborting the request when ctx is cancelled
+func (run *Runner) RunContext(ctx context.Context, r *route.Route) TestResult {
+	result := TestResult{
+		RouteID:   r.ID,
+		RouteName: r.Name,
+		Method:    string(r.Method),
+		Path:      r.Path,
+	}
+	req, path, err := run.build(r)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Path = path
+	resp, err := run.send(ctx, req)
+	if err != nil {
+		result.Error = err.Error()
+		if ctx.Err() != nil {
+			result.Error = "cancelled: " + context.Cause(ctx).Error()
+		}
+		return result
+	}
+	result.StatusCode = resp.StatusCode
+	result.Duration = resp.Duration
+	result.ResponseHeaders = resp.Headers
+	result.ResponseBody = string(resp.Body)
+	result.Assertions = r.Expect.Evaluate(resp)
+	if r.ResponseSchema != "" {
+		result.Assertions = append(result.Assertions, run.validateSchema(r.ResponseSchema, resp.Body))
+	}
+	for _, check := range run.Checks {
+		result.Assertions = append(result.Assertions, check(r, resp)...)
+	}
+	result.Extracted, result.Assertions = run.extract(r, resp, result.Assertions)
+	return result
+}
+
+// validateSchema checks body against the schema at ref, loading each schema once per runner
+func (run *Runner) validateSchema(ref string, body []byte) assert.Result {
+	result := assert.Result{Name: "schema"}
+	run.schemasMu.Lock()
+	s, ok := run.schemas[ref]
+	if !ok {
+		var err error
+		if s, err = schema.Load(ref); err != nil {
+			run.schemasMu.Unlock()
+			result.Message = "failed to load schema: " + err.Error()
+			return result
+		}
+		if run.schemas == nil {
+			run.schemas = make(map[string]*schema.Schema)
+		}
+		run.schemas[ref] = s
+	}
+	run.schemasMu.Unlock()
+
+	violations, err := s.ValidateBytes(body)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	if len(violations) == 0 {
+		result.Passed = true
+		return result
+	}
+	lines := []string{fmt.Sprintf("%d violation(s)", len(violations))}
+	for _, v := range violations {
+		lines = append(lines, "  "+v.String())
+	}
+	result.Message = strings.Join(lines, "\n")
+	return result
+}
+
+// extract applies the route's extractions to resp, storing the values as variables
+// for later routes. Failed extractions are added to results.
+func (run *Runner) extract(r *route.Route, resp *api.Response, results []assert.Result) (map[string]string, []assert.Result) {
+	if len(r.Extract) == 0 {
+		return nil, results
+	}
+	extracted := make(map[string]string, len(r.Extract))
+	for _, e := range r.Extract {
+		value, err := e.Apply(resp)
+		if err != nil {
+			results = append(results, assert.Result{Name: "extract " + e.Name, Message: err.Error()})
+			continue
+		}
+		extracted[e.Name] = value
+	}
+	run.varsMu.Lock()
+	if run.Vars == nil {
+		run.Vars = make(map[string]string)
+	}
+	maps.Copy(run.Vars, extracted)
+	run.varsMu.Unlock()
+	return extracted, results
+}
+
+// Login sends the login route without auth and extracts the token at tokenPath
+func (run *Runner) Login(login *route.Route, tokenPath string) (string, error) {
+	req, err := run.Request(login)
+	if err != nil {
+		return "", err
+	}
+	req.NoAuth = true
+	resp, err := run.Client.Send(req)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("login route '%s' returned status %d", login.Name, resp.StatusCode)
+	}
+	value, err := jsonpath.LookupBytes(resp.Body, tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("token not found in login response: %w", err)
+	}
+	token, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("value at %s is not a string", tokenPath)
+	}
+	return token, nil
+}
@@ -0,0 +1,173 @@
+package runner
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/schema"
+)
+
+func fakeJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, `{"exp":%d}`, exp.Unix()))
+	return "e30." + payload + ".sig"
+}
+
+func TestRunJWTLoginAndRefresh(t *testing.T) {
+	// Arrange: a server that issues tokens and only accepts the latest one
+	logins := 0
+	current := ""
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		current = fakeJWT(time.Now().Add(time.Hour).Add(time.Duration(logins) * time.Second))
+		fmt.Fprintf(w, `{"data": {"access_token": %q}}`, current)
+	})
+	mux.HandleFunc("GET /me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+current {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	p := &project.Project{BaseURL: server.URL}
+	login := &route.Route{Name: "Login", Method: route.POST, Path: "/login", Public: true}
+	profile := &auth.Profile{Type: auth.JWT, LoginRouteID: 1, TokenPath: "$.data.access_token"}
+	saved := 0
+	run, err := New(p, api.Config{Timeout: 5 * time.Second}, profile, login, func(*auth.Profile) error {
+		saved++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	me := &route.Route{Name: "Me", Method: route.GET, Path: "/me"}
+
+	// Act: first run logs in, then the server rotates the token behind our back
+	first := run.Run(me)
+	current = "rotated"
+	second := run.Run(me)
+
+	// Assert: both succeed, the 401 triggered exactly one extra login
+	if first.StatusCode != http.StatusOK || second.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 twice, got %d (%s) and %d (%s)", first.StatusCode, first.Error, second.StatusCode, second.Error)
+	}
+	if logins != 2 {
+		t.Errorf("expected 2 logins, got %d", logins)
+	}
+	if saved != 2 {
+		t.Errorf("expected token to be cached twice, got %d", saved)
+	}
+	if profile.TokenExpiry.IsZero() {
+		t.Error("expected token expiry to be decoded from exp claim")
+	}
+}
+
+func TestHTTPRequestUsesCachedToken(t *testing.T) {
+	p := &project.Project{BaseURL: "http://localhost"}
+	login := &route.Route{Name: "Login", Method: route.POST, Path: "/login", Public: true}
+	profile := &auth.Profile{Type: auth.JWT, LoginRouteID: 1, TokenPath: "$.token"}
+	run, err := New(p, api.Config{Timeout: time.Second}, profile, login, func(*auth.Profile) error {
+		t.Error("expected no token to be saved")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	run.Client = nil // any login attempt would panic
+	me := &route.Route{Name: "Me", Method: route.GET, Path: "/me"}
+
+	req, err := run.HTTPRequest(me)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer "+auth.TokenVariable {
+		t.Errorf("expected token placeholder without a cached token, got %q", got)
+	}
+
+	profile.Token = fakeJWT(time.Now().Add(time.Hour))
+	req, err = run.HTTPRequest(me)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer "+profile.Token {
+		t.Errorf("expected cached token, got %q", got)
+	}
+}
+
+func TestRunMissingParam(t *testing.T) {
+	run, err := New(&project.Project{BaseURL: "http://localhost"}, api.Config{Timeout: time.Second}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	result := run.Run(&route.Route{Name: "Get User", Method: route.GET, Path: "/users/{id}", Params: []route.Param{{Name: "id"}}})
+	if result.Error == "" {
+		t.Fatal("expected error for unbound path parameter")
+	}
+}
+
+func TestRunResponseSchema(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "7"}`))
+	}))
+	defer server.Close()
+	run, err := New(&project.Project{BaseURL: server.URL}, api.Config{Timeout: time.Second}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	result := run.Run(&route.Route{Name: "Get", Method: route.GET, Path: "/", ResponseSchema: `{"properties": {"id": {"type": "integer"}}}`})
+
+	if result.Passed() || len(result.Assertions) != 1 {
+		t.Fatalf("expected schema failure, got %+v", result.Assertions)
+	}
+	if msg := result.Assertions[0].Message; !strings.Contains(msg, "/id: expected integer, got string") {
+		t.Errorf("expected violation with pointer, got %s", msg)
+	}
+}
+
+func TestRunResponseSchemaFromOtherDirectory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "7"}`))
+	}))
+	defer server.Close()
+	repo := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repo, "schemas", "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "schemas", "user.json"), []byte(`{"properties": {"id": {"type": "integer"}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// stored from the repository root, run from a subdirectory
+	t.Chdir(repo)
+	ref, err := schema.Resolve("schemas/user.json", ".")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Chdir(filepath.Join(repo, "schemas", "nested"))
+	run, err := New(&project.Project{BaseURL: server.URL}, api.Config{Timeout: time.Second}, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	result := run.Run(&route.Route{Name: "Get", Method: route.GET, Path: "/", ResponseSchema: ref})
+
+	if len(result.Assertions) != 1 || !strings.Contains(result.Assertions[0].Message, "/id: expected integer, got string") {
+		t.Errorf("expected the schema to load and report a violation, got %+v", result.Assertions)
+	}
+}
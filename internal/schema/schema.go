@@ -0,0 +1,217 @@
+// Package schema validates JSON documents against JSON Schema, including the
+// OpenAPI dialect (nullable, boolean exclusive bounds)
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/jsonpath"
+	"github.com/raworiginal/goapi/internal/text"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxRefDepth stops runaway $ref chains and recursive schemas
+const MaxRefDepth = 32
+
+// Schema is a JSON Schema together with the document it came from, which
+// $ref pointers such as #/components/schemas/User are resolved against
+type Schema struct {
+	root map[string]any
+	node any // an object, or a boolean schema
+}
+
+// Violation is a part of a document that does not satisfy a schema
+type Violation struct {
+	Pointer string // JSON pointer to the offending value, empty for the whole document
+	Message string
+}
+
+func (v Violation) String() string {
+	pointer := v.Pointer
+	if pointer == "" {
+		pointer = "(root)"
+	}
+	return pointer + ": " + v.Message
+}
+
+// New creates a schema for node, resolving references against root
+func New(root, node map[string]any) *Schema {
+	return &Schema{root: root, node: node}
+}
+
+// Parse reads an inline JSON or YAML schema
+func Parse(data []byte) (*Schema, error) {
+	doc, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root: doc, node: doc}, nil
+}
+
+// Load resolves a schema reference: inline JSON starting with '{', a JSON or
+// YAML file, or a file with a fragment naming part of it. The fragment is a JSON
+// pointer such as spec.yaml#/components/schemas/User, or just the component name
+// as in spec.yaml#User.
+func Load(ref string) (*Schema, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "{") {
+		return Parse([]byte(ref))
+	}
+	path, fragment, _ := strings.Cut(ref, "#")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if fragment == "" {
+		return &Schema{root: doc, node: doc}, nil
+	}
+	pointers := []string{fragment}
+	if !strings.HasPrefix(fragment, "/") {
+		pointers = []string{"/components/schemas/" + fragment, "/definitions/" + fragment}
+	}
+	for _, pointer := range pointers {
+		if node, ok := ResolvePointer(doc, pointer); ok {
+			return &Schema{root: doc, node: node}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: no schema at #%s", path, fragment)
+}
+
+// Resolve returns ref with a relative file path made absolute against dir, so it
+// loads from any working directory. Inline schemas are returned unchanged.
+func Resolve(ref, dir string) (string, error) {
+	path, fragment, hasFragment := strings.Cut(strings.TrimSpace(ref), "#")
+	if strings.HasPrefix(ref, "{") || path == "" || filepath.IsAbs(path) {
+		return ref, nil
+	}
+	abs, err := filepath.Abs(filepath.Join(dir, path))
+	if err != nil {
+		return "", err
+	}
+	if hasFragment {
+		return abs + "#" + fragment, nil
+	}
+	return abs, nil
+}
+
+// Relative returns ref with an absolute file path made relative to dir, the
+// reverse of Resolve. Other references are returned unchanged.
+func Relative(ref, dir string) string {
+	path, fragment, hasFragment := strings.Cut(ref, "#")
+	if strings.HasPrefix(ref, "{") || !filepath.IsAbs(path) {
+		return ref
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return ref
+	}
+	rel = filepath.ToSlash(rel)
+	if hasFragment {
+		return rel + "#" + fragment
+	}
+	return rel
+}
+
+// decode reads JSON or YAML into generic values with string keys
+func decode(data []byte) (map[string]any, error) {
+	var decoded any
+	if err := yaml.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	doc, ok := Normalize(decoded).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid schema: expected an object")
+	}
+	return doc, nil
+}
+
+// Normalize converts YAML maps with non-string keys, such as unquoted status codes, to map[string]any
+func Normalize(v any) any {
+	switch t := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, value := range t {
+			m[fmt.Sprint(k)] = Normalize(value)
+		}
+		return m
+	case map[string]any:
+		for k, value := range t {
+			t[k] = Normalize(value)
+		}
+		return t
+	case []any:
+		for i, value := range t {
+			t[i] = Normalize(value)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// ResolvePointer returns the value at a JSON pointer such as /components/schemas/User
+func ResolvePointer(doc any, pointer string) (any, bool) {
+	current := doc
+	if pointer == "" || pointer == "/" {
+		return current, true
+	}
+	for _, part := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		switch v := current.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			current = next
+		case []any:
+			var i int
+			if _, err := fmt.Sscan(part, &i); err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			current = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// ValidateBytes decodes body as JSON and validates it
+func (s *Schema) ValidateBytes(body []byte) ([]Violation, error) {
+	doc, err := jsonpath.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(doc), nil
+}
+
+// Validate checks a decoded JSON document, as returned by jsonpath.Decode or
+// json.Unmarshal, and returns every violation found
+func (s *Schema) Validate(doc any) []Violation {
+	v := &validator{root: s.root}
+	v.validate(s.node, doc, "", 0)
+	return v.violations
+}
+
+// Pointer escapes key and appends it to a JSON pointer
+func Pointer(pointer, key string) string {
+	return pointer + "/" + strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// describe formats a value for a violation message
+func describe(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return text.Shorten(string(data), 60)
+}
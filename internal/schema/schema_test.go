@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const spec = `
+openapi: 3.0.3
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, email]
+      additionalProperties: false
+      properties:
+        id: {type: integer, minimum: 1}
+        email: {type: string, format: email}
+        nickname: {type: string, nullable: true}
+        tags:
+          type: array
+          items: {$ref: '#/components/schemas/Tag'}
+    Tag:
+      type: string
+      enum: [admin, staff]
+`
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "openapi.yaml")
+	if err := os.WriteFile(path, []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path + "#User")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	valid, err := s.ValidateBytes([]byte(`{"id": 1, "email": "a@example.com", "nickname": null, "tags": ["admin"]}`))
+	if err != nil || len(valid) != 0 {
+		t.Errorf("expected valid document, got %v %v", valid, err)
+	}
+
+	violations, err := s.ValidateBytes([]byte(`{"id": 0, "email": "nope", "tags": ["admin", "root"], "extra": true}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var lines []string
+	for _, v := range violations {
+		lines = append(lines, v.String())
+	}
+	want := []string{
+		`/email: "nope" is not a valid email`,
+		`/extra: property "extra" is not allowed`,
+		`/id: 0 is less than the minimum 1`,
+		`/tags/1: "root" is not one of ["admin","staff"]`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected violations:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestValidateInline(t *testing.T) {
+	s, err := Load(`{"type": ["array", "null"], "items": {"type": "object", "required": ["a/b"]}, "minItems": 2}`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	violations, _ := s.ValidateBytes([]byte(`[{"x": 1.5}]`))
+
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", violations)
+	}
+	if violations[0].Pointer != "" || violations[1].String() != `/0: missing required property "a/b"` {
+		t.Errorf("unexpected violations: %v", violations)
+	}
+	if v, _ := s.ValidateBytes([]byte(`"x"`)); len(v) != 1 || v[0].Message != "expected array or null, got string" {
+		t.Errorf("unexpected type violation: %v", v)
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	s, err := Parse([]byte(`{"oneOf": [{"type": "integer"}, {"type": "number"}], "not": {"const": 3}}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if v := s.Validate(1.5); len(v) != 0 {
+		t.Errorf("expected 1.5 to match only number, got %v", v)
+	}
+	if v := s.Validate(2); len(v) != 1 {
+		t.Errorf("expected 2 to match both oneOf schemas, got %v", v)
+	}
+}
+
+func TestLoadMissingFragment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.json")
+	if err := os.WriteFile(path, []byte(`{"definitions": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path + "#/definitions/User"); err == nil {
+		t.Error("expected error for missing schema")
+	}
+}
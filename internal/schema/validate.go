@@ -0,0 +1,350 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type validator struct {
+	root       map[string]any
+	violations []Violation
+}
+
+func (v *validator) fail(pointer, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Pointer: pointer, Message: fmt.Sprintf(format, args...)})
+}
+
+// check validates doc against node without recording violations, for anyOf, oneOf and not
+func (v *validator) check(node, doc any, depth int) bool {
+	sub := &validator{root: v.root}
+	sub.validate(node, doc, "", depth)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(node, doc any, pointer string, depth int) {
+	if depth > MaxRefDepth {
+		v.fail(pointer, "schema nesting too deep")
+		return
+	}
+	if b, ok := node.(bool); ok {
+		if !b {
+			v.fail(pointer, "no value is allowed here")
+		}
+		return
+	}
+	schema, _ := node.(map[string]any)
+	if schema == nil {
+		return
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		target, found := ResolvePointer(v.root, strings.TrimPrefix(ref, "#"))
+		if !strings.HasPrefix(ref, "#") || !found {
+			v.fail(pointer, "cannot resolve $ref %s", ref)
+			return
+		}
+		v.validate(target, doc, pointer, depth+1)
+		// OpenAPI 3.0 ignores keywords next to $ref; 3.1 applies them, so fall through
+	}
+
+	if doc == nil && schema["nullable"] == true {
+		return
+	}
+	if types := schemaTypes(schema); len(types) > 0 {
+		if !slices.ContainsFunc(types, func(t string) bool { return hasType(doc, t) }) {
+			v.fail(pointer, "expected %s, got %s", strings.Join(types, " or "), typeOf(doc))
+			return
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool { return equal(e, doc) }) {
+			v.fail(pointer, "%s is not one of %s", describe(doc), describe(enum))
+		}
+	}
+	if c, ok := schema["const"]; ok && !equal(c, doc) {
+		v.fail(pointer, "expected %s, got %s", describe(c), describe(doc))
+	}
+
+	switch value := doc.(type) {
+	case map[string]any:
+		v.validateObject(schema, value, pointer, depth)
+	case []any:
+		v.validateArray(schema, value, pointer, depth)
+	case string:
+		v.validateString(schema, value, pointer)
+	default:
+		if n, ok := number(doc); ok {
+			v.validateNumber(schema, n, pointer)
+		}
+	}
+
+	for _, sub := range list(schema["allOf"]) {
+		v.validate(sub, doc, pointer, depth+1)
+	}
+	if anyOf := list(schema["anyOf"]); len(anyOf) > 0 {
+		if !slices.ContainsFunc(anyOf, func(sub any) bool { return v.check(sub, doc, depth+1) }) {
+			v.fail(pointer, "does not match any of the allowed schemas")
+		}
+	}
+	if oneOf := list(schema["oneOf"]); len(oneOf) > 0 {
+		matches := 0
+		for _, sub := range oneOf {
+			if v.check(sub, doc, depth+1) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			v.fail(pointer, "matches %d of the oneOf schemas, expected exactly 1", matches)
+		}
+	}
+	if not, ok := schema["not"]; ok && v.check(not, doc, depth+1) {
+		v.fail(pointer, "must not match the schema in 'not'")
+	}
+}
+
+func (v *validator) validateObject(schema, obj map[string]any, pointer string, depth int) {
+	for _, name := range list(schema["required"]) {
+		key, _ := name.(string)
+		if _, ok := obj[key]; !ok {
+			v.fail(pointer, "missing required property %q", key)
+		}
+	}
+	if n, ok := number(schema["minProperties"]); ok && float64(len(obj)) < n {
+		v.fail(pointer, "has %d properties, expected at least %v", len(obj), n)
+	}
+	if n, ok := number(schema["maxProperties"]); ok && float64(len(obj)) > n {
+		v.fail(pointer, "has %d properties, expected at most %v", len(obj), n)
+	}
+	props, _ := schema["properties"].(map[string]any)
+	patterns, _ := schema["patternProperties"].(map[string]any)
+	additional, hasAdditional := schema["additionalProperties"]
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		child := Pointer(pointer, key)
+		matched := false
+		if prop, ok := props[key]; ok {
+			v.validate(prop, obj[key], child, depth+1)
+			matched = true
+		}
+		for pattern, sub := range patterns {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				v.validate(sub, obj[key], child, depth+1)
+				matched = true
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if additional == false {
+			v.fail(child, "property %q is not allowed", key)
+		} else {
+			v.validate(additional, obj[key], child, depth+1)
+		}
+	}
+}
+
+func (v *validator) validateArray(schema map[string]any, arr []any, pointer string, depth int) {
+	if n, ok := number(schema["minItems"]); ok && float64(len(arr)) < n {
+		v.fail(pointer, "has %d items, expected at least %v", len(arr), n)
+	}
+	if n, ok := number(schema["maxItems"]); ok && float64(len(arr)) > n {
+		v.fail(pointer, "has %d items, expected at most %v", len(arr), n)
+	}
+	if schema["uniqueItems"] == true {
+		for i := range arr {
+			for j := range i {
+				if equal(arr[i], arr[j]) {
+					v.fail(Pointer(pointer, strconv.Itoa(i)), "duplicates item %d", j)
+				}
+			}
+		}
+	}
+	prefix := list(schema["prefixItems"])
+	for i, item := range arr {
+		child := Pointer(pointer, strconv.Itoa(i))
+		if i < len(prefix) {
+			v.validate(prefix[i], item, child, depth+1)
+		} else if items, ok := schema["items"]; ok {
+			v.validate(items, item, child, depth+1)
+		}
+	}
+}
+
+func (v *validator) validateString(schema map[string]any, s, pointer string) {
+	length := float64(utf8.RuneCountInString(s))
+	if n, ok := number(schema["minLength"]); ok && length < n {
+		v.fail(pointer, "length %v is less than %v", length, n)
+	}
+	if n, ok := number(schema["maxLength"]); ok && length > n {
+		v.fail(pointer, "length %v is greater than %v", length, n)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			v.fail(pointer, "invalid pattern %q: %v", pattern, err)
+		} else if !re.MatchString(s) {
+			v.fail(pointer, "%s does not match pattern %q", describe(s), pattern)
+		}
+	}
+	if format, ok := schema["format"].(string); ok && !validFormat(format, s) {
+		v.fail(pointer, "%s is not a valid %s", describe(s), format)
+	}
+}
+
+func (v *validator) validateNumber(schema map[string]any, n float64, pointer string) {
+	if min, ok := number(schema["minimum"]); ok {
+		if schema["exclusiveMinimum"] == true && n <= min {
+			v.fail(pointer, "%v is not greater than %v", n, min)
+		} else if n < min {
+			v.fail(pointer, "%v is less than the minimum %v", n, min)
+		}
+	}
+	if max, ok := number(schema["maximum"]); ok {
+		if schema["exclusiveMaximum"] == true && n >= max {
+			v.fail(pointer, "%v is not less than %v", n, max)
+		} else if n > max {
+			v.fail(pointer, "%v is greater than the maximum %v", n, max)
+		}
+	}
+	if min, ok := number(schema["exclusiveMinimum"]); ok && n <= min {
+		v.fail(pointer, "%v is not greater than %v", n, min)
+	}
+	if max, ok := number(schema["exclusiveMaximum"]); ok && n >= max {
+		v.fail(pointer, "%v is not less than %v", n, max)
+	}
+	if m, ok := number(schema["multipleOf"]); ok && m > 0 {
+		if q := n / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.fail(pointer, "%v is not a multiple of %v", n, m)
+		}
+	}
+}
+
+// schemaTypes returns the allowed types, from a single type or an OpenAPI 3.1 type array
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func hasType(doc any, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := number(doc)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := number(doc)
+		return ok
+	}
+	return typeOf(doc) == t
+}
+
+func typeOf(doc any) string {
+	switch doc.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	if n, ok := number(doc); ok {
+		if n == math.Trunc(n) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", doc)
+}
+
+// number converts the numeric types produced by JSON and YAML decoding
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// equal compares two decoded JSON values, treating numbers by value
+func equal(a, b any) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for key, value := range x {
+			if other, ok := y[key]; !ok || !equal(value, other) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		return ok && slices.EqualFunc(x, y, equal)
+	}
+	return a == b
+}
+
+func list(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat checks the common string formats; unknown formats always pass
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	}
+	return true
+}
@@ -0,0 +1,252 @@
+// Package snapshot stores normalized response bodies and compares later responses against them
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/jsonpath"
+	"github.com/raworiginal/goapi/internal/text"
+)
+
+// Ignored replaces values at ignored paths, so a key that disappears is still reported
+const Ignored = "<ignored>"
+
+// maxDifferences is how many differences a failed comparison lists
+const maxDifferences = 20
+
+// Snapshot is the stored response body of a route
+type Snapshot struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ProjectID   uint      `gorm:"index" json:"project_id"`
+	RouteID     uint      `gorm:"uniqueIndex" json:"route_id"`
+	Body        string    `json:"body"` // normalized with Normalize
+	DateUpdated time.Time `gorm:"autoUpdateTime" json:"date_updated"`
+}
+
+// ValidateIgnore checks that path is a JSON path usable as an ignored path
+func ValidateIgnore(path string) error {
+	segments, err := jsonpath.Parse(path)
+	if err != nil {
+		return err
+	}
+	if len(segments) == 0 {
+		return fmt.Errorf("invalid ignored path %s: ignoring the whole body disables the snapshot", path)
+	}
+	return nil
+}
+
+// Normalize prepares body for storage and comparison. JSON bodies are re-encoded with
+// sorted keys and indentation, and values at the ignored paths replaced with Ignored.
+// Other bodies are kept as text with line endings normalized.
+func Normalize(body []byte, ignore []string) (string, error) {
+	doc, err := jsonpath.Decode(body)
+	if err != nil {
+		text := strings.ReplaceAll(string(body), "\r\n", "\n")
+		return strings.TrimRight(text, "\n"), nil
+	}
+	for _, path := range ignore {
+		segments, err := jsonpath.Parse(path)
+		if err != nil {
+			return "", err
+		}
+		doc = replace(doc, segments)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// replace sets every value matching segments to Ignored, leaving missing paths alone
+func replace(doc any, segments []jsonpath.Segment) any {
+	if len(segments) == 0 {
+		return Ignored
+	}
+	seg, rest := segments[0], segments[1:]
+	switch v := doc.(type) {
+	case map[string]any:
+		if seg.Wildcard {
+			for key, child := range v {
+				v[key] = replace(child, rest)
+			}
+		} else if child, ok := v[seg.Key]; ok && !seg.IsIndex {
+			v[seg.Key] = replace(child, rest)
+		}
+	case []any:
+		if seg.Wildcard {
+			for i, child := range v {
+				v[i] = replace(child, rest)
+			}
+		} else if seg.IsIndex && seg.Index < len(v) {
+			v[seg.Index] = replace(v[seg.Index], rest)
+		}
+	}
+	return doc
+}
+
+// Kind is how a value differs from the snapshot
+type Kind string
+
+const (
+	Added   Kind = "+"
+	Removed Kind = "-"
+	Changed Kind = "~"
+)
+
+// Difference is one value that differs between a snapshot and a response
+type Difference struct {
+	Path     string // JSON path, or "line N" for non-JSON bodies
+	Kind     Kind
+	Expected any
+	Actual   any
+}
+
+func (d Difference) String() string {
+	switch d.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", d.Path, format(d.Actual))
+	case Removed:
+		return fmt.Sprintf("- %s: %s", d.Path, format(d.Expected))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", d.Path, format(d.Expected), format(d.Actual))
+	}
+}
+
+// Diff compares two normalized bodies. JSON bodies are compared value by value,
+// anything else line by line.
+func Diff(expected, actual string) []Difference {
+	if expected == actual {
+		return nil
+	}
+	want, err1 := jsonpath.Decode([]byte(expected))
+	got, err2 := jsonpath.Decode([]byte(actual))
+	if err1 != nil || err2 != nil {
+		return diffLines(expected, actual)
+	}
+	return diffValues("$", want, got, nil)
+}
+
+func diffValues(path string, want, got any, diffs []Difference) []Difference {
+	switch w := want.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for key := range w {
+			keys = append(keys, key)
+		}
+		for key := range g {
+			if _, ok := w[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			wv, inWant := w[key]
+			gv, inGot := g[key]
+			child := jsonpath.Child(path, key)
+			switch {
+			case !inGot:
+				diffs = append(diffs, Difference{Path: child, Kind: Removed, Expected: wv})
+			case !inWant:
+				diffs = append(diffs, Difference{Path: child, Kind: Added, Actual: gv})
+			default:
+				diffs = diffValues(child, wv, gv, diffs)
+			}
+		}
+		return diffs
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			break
+		}
+		for i := range max(len(w), len(g)) {
+			child := jsonpath.Index(path, i)
+			switch {
+			case i >= len(g):
+				diffs = append(diffs, Difference{Path: child, Kind: Removed, Expected: w[i]})
+			case i >= len(w):
+				diffs = append(diffs, Difference{Path: child, Kind: Added, Actual: g[i]})
+			default:
+				diffs = diffValues(child, w[i], g[i], diffs)
+			}
+		}
+		return diffs
+	default:
+		if want == got {
+			return diffs
+		}
+	}
+	return append(diffs, Difference{Path: path, Kind: Changed, Expected: want, Actual: got})
+}
+
+func diffLines(expected, actual string) []Difference {
+	want, got := strings.Split(expected, "\n"), strings.Split(actual, "\n")
+	var diffs []Difference
+	for i := range max(len(want), len(got)) {
+		path := fmt.Sprintf("line %d", i+1)
+		switch {
+		case i >= len(got):
+			diffs = append(diffs, Difference{Path: path, Kind: Removed, Expected: want[i]})
+		case i >= len(want):
+			diffs = append(diffs, Difference{Path: path, Kind: Added, Actual: got[i]})
+		case want[i] != got[i]:
+			diffs = append(diffs, Difference{Path: path, Kind: Changed, Expected: want[i], Actual: got[i]})
+		}
+	}
+	return diffs
+}
+
+// format renders a value compactly for a diff line
+func format(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return text.Shorten(strings.TrimSuffix(buf.String(), "\n"), 80)
+}
+
+// Compare checks body against the snapshot and describes any differences. The ignored
+// paths are applied to both sides, so paths ignored after the snapshot was taken count.
+func (s *Snapshot) Compare(body []byte, ignore []string) assert.Result {
+	result := assert.Result{Name: "snapshot"}
+	expected, err := Normalize([]byte(s.Body), ignore)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	actual, err := Normalize(body, ignore)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	diffs := Diff(expected, actual)
+	if len(diffs) == 0 {
+		result.Passed = true
+		return result
+	}
+	lines := []string{fmt.Sprintf("%d difference(s) from snapshot", len(diffs))}
+	for i, d := range diffs {
+		if i == maxDifferences {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(diffs)-maxDifferences))
+			break
+		}
+		lines = append(lines, "  "+d.String())
+	}
+	result.Message = strings.Join(lines, "\n")
+	return result
+}
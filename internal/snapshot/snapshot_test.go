@@ -0,0 +1,81 @@
+package snapshot
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNormalize(t *testing.T) {
+	body := []byte(`{"b": 1, "a": {"id": 9, "at": "2024-01-01"}, "items": [{"id": 1}, {"id": 2}]}`)
+
+	got, err := Normalize(body, []string{"$.a.at", "$.items[*].id", "$.missing"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := `{
+  "a": {
+    "at": "<ignored>",
+    "id": 9
+  },
+  "b": 1,
+  "items": [
+    {
+      "id": "<ignored>"
+    },
+    {
+      "id": "<ignored>"
+    }
+  ]
+}`
+	if got != want {
+		t.Errorf("expected sorted body with ignored values, got\n%s", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	expected := `{"name": "Ann", "tags": ["a", "b"], "old": true, "n": 1}`
+	actual := `{"name": "Bob", "tags": ["a"], "new": null, "n": 1}`
+
+	diffs := Diff(expected, actual)
+
+	var lines []string
+	for _, d := range diffs {
+		lines = append(lines, d.String())
+	}
+	want := []string{`~ $.name: "Ann" -> "Bob"`, `+ $.new: null`, `- $.old: true`, `- $.tags[1]: "b"`}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected differences:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestDiffText(t *testing.T) {
+	diffs := Diff("ok\nsame", "fail\nsame\nextra")
+
+	if len(diffs) != 2 || diffs[0].Path != "line 1" || diffs[1].Kind != Added {
+		t.Errorf("unexpected differences: %+v", diffs)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	s := &Snapshot{Body: `{"id": 1, "name": "Ann"}`}
+
+	if r := s.Compare([]byte(`{"name":"Ann","id":2}`), []string{"$.id"}); !r.Passed {
+		t.Errorf("expected ignored id to pass, got %s", r.Message)
+	}
+	r := s.Compare([]byte(`{"name":"Bob","id":1}`), nil)
+	if r.Passed || !strings.Contains(r.Message, `~ $.name: "Ann" -> "Bob"`) {
+		t.Errorf("expected name difference, got %+v", r)
+	}
+}
+
+func TestCompareLongValuesStayValidUTF8(t *testing.T) {
+	s := &Snapshot{Body: `{"name": "` + strings.Repeat("é", 60) + `"}`}
+
+	result := s.Compare([]byte(`{"name": "`+strings.Repeat("ü", 60)+`"}`), nil)
+
+	if result.Passed || !utf8.ValidString(result.Message) {
+		t.Errorf("expected a valid UTF-8 difference message, got %q", result.Message)
+	}
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/raworiginal/goapi/internal/auth"
+)
+
+// SetAuth creates or replaces the authentication profile of a project
+func SetAuth(a *auth.Profile) error {
+	existing, err := GetAuth(a.ProjectID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		a.ID = existing.ID
+	}
+	return DB.Save(a).Error
+}
+
+// GetAuth retrieves the authentication profile of a project, or nil if none is set
+func GetAuth(projectID uint) (*auth.Profile, error) {
+	var profiles []*auth.Profile
+	if err := DB.Where("project_id = ?", projectID).Limit(1).Find(&profiles).Error; err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	return profiles[0], nil
+}
+
+// ClearAuth removes the authentication profile of a project
+func ClearAuth(projectID uint) error {
+	result := DB.Where("project_id = ?", projectID).Delete(&auth.Profile{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no auth profile set for project %v", projectID)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/raworiginal/goapi/internal/environment"
+	"gorm.io/gorm"
+)
+
+// CreateEnvironment adds a new environment to a project
+func CreateEnvironment(e *environment.Environment) error {
+	if e.Name == "" {
+		return fmt.Errorf("environment name cannot be empty")
+	}
+	return DB.Create(e).Error
+}
+
+// ListEnvironmentsByProject retrieves all environments of a project
+func ListEnvironmentsByProject(projectID uint) ([]*environment.Environment, error) {
+	var envs []*environment.Environment
+	if err := DB.Where("project_id = ?", projectID).Order("name").Find(&envs).Error; err != nil {
+		return nil, err
+	}
+	return envs, nil
+}
+
+// GetEnvironmentByName retrieves an environment by project ID and name
+func GetEnvironmentByName(projectID uint, name string) (*environment.Environment, error) {
+	var e environment.Environment
+	if err := DB.Where("name = ? AND project_id = ?", name, projectID).First(&e).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// GetActiveEnvironment retrieves the environment in use for a project, or nil if none is selected
+func GetActiveEnvironment(projectID uint) (*environment.Environment, error) {
+	var envs []*environment.Environment
+	if err := DB.Where("project_id = ? AND active = ?", projectID, true).Limit(1).Find(&envs).Error; err != nil {
+		return nil, err
+	}
+	if len(envs) == 0 {
+		return nil, nil
+	}
+	return envs[0], nil
+}
+
+// UpdateEnvironment modifies an existing environment
+func UpdateEnvironment(id uint, updates *environment.UpdateEnvironmentInput) error {
+	result := DB.Model(&environment.Environment{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no environment found with id: %v", id)
+	}
+	return nil
+}
+
+// UseEnvironment makes the environment with the given ID the only active one in its project.
+// An ID of 0 deactivates all environments of the project.
+func UseEnvironment(projectID, id uint) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&environment.Environment{}).Where("project_id = ?", projectID).Update("active", false).Error; err != nil {
+			return err
+		}
+		if id == 0 {
+			return nil
+		}
+		return tx.Model(&environment.Environment{}).Where("id = ? AND project_id = ?", id, projectID).Update("active", true).Error
+	})
+}
+
+// DeleteEnvironment removes an environment by ID
+func DeleteEnvironment(id uint) error {
+	result := DB.Where("id = ?", id).Delete(&environment.Environment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("environment not found: environment %v", id)
+	}
+	return nil
+}
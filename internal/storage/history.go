@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"github.com/raworiginal/goapi/internal/history"
+)
+
+// CreateRun records a test run together with its results
+func CreateRun(run *history.Run) error {
+	return DB.Create(run).Error
+}
+
+// ListRuns retrieves the most recent runs of a project, newest first
+func ListRuns(projectID uint, limit int) ([]*history.Run, error) {
+	var runs []*history.Run
+	query := DB.Where("project_id = ?", projectID).Order("started_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// GetRun retrieves a run by ID with its results
+func GetRun(id uint) (*history.Run, error) {
+	var run history.Run
+	if err := DB.Preload("Results").Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -24,6 +24,18 @@ func GetProject(name string) (*project.Project, error) {
 	return &p, nil
 }
 
+// FindProject retrieves a project by name, or nil if it does not exist
+func FindProject(name string) (*project.Project, error) {
+	var projects []*project.Project
+	if err := DB.Where("name = ?", name).Limit(1).Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, nil
+	}
+	return projects[0], nil
+}
+
 // ListProjects retrieves all projects
 func ListProjects() ([]*project.Project, error) {
 	var projects []*project.Project
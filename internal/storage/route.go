@@ -3,7 +3,10 @@ package storage
 import (
 	"fmt"
 
+	"github.com/raworiginal/goapi/internal/project"
 	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/snapshot"
+	"gorm.io/gorm"
 )
 
 // CreateRoute Adds new route to project in database
@@ -11,6 +14,13 @@ func CreateRoute(r *route.Route) error {
 	if r.Name == "" {
 		return fmt.Errorf("route name cannot be empty")
 	}
+	if r.Order == 0 {
+		next, err := nextRouteOrder(DB, r.ProjectID)
+		if err != nil {
+			return err
+		}
+		r.Order = next
+	}
 	result := DB.Create(r)
 	if result.Error != nil {
 		return result.Error
@@ -18,10 +28,19 @@ func CreateRoute(r *route.Route) error {
 	return nil
 }
 
-// ListRoutesByProject retreieves all routes for a project
+// nextRouteOrder returns the order that places a new route after the project's existing routes
+func nextRouteOrder(tx *gorm.DB, projectID uint) (int, error) {
+	var last int
+	if err := tx.Model(&route.Route{}).Where("project_id = ?", projectID).Select("COALESCE(MAX(run_order), 0)").Scan(&last).Error; err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+// ListRoutesByProject retreieves all routes for a project in run order
 func ListRoutesByProject(projectID uint) ([]*route.Route, error) {
 	var routes []*route.Route
-	result := DB.Where("project_id = ?", projectID).Find(&routes)
+	result := DB.Where("project_id = ?", projectID).Order("run_order, id").Find(&routes)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -58,14 +77,46 @@ func UpdateRoute(id uint, updates *route.UpdateRouteInput) error {
 	return nil
 }
 
-// DeleteRoute removes a route by ID
+// DeleteRoute removes a route by ID along with its snapshot
 func DeleteRoute(id uint) error {
-	result := DB.Where("id = ?", id).Delete(&route.Route{})
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("route not found: route %v", id)
-	}
-	return nil
+	return DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&route.Route{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("route not found: route %v", id)
+		}
+		return tx.Where("route_id = ?", id).Delete(&snapshot.Snapshot{}).Error
+	})
+}
+
+// ImportRoutes creates routes in a project in a single transaction, creating
+// the project first if it has no ID yet
+func ImportRoutes(p *project.Project, routes []*route.Route) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if p.ID == 0 {
+			if err := tx.Create(p).Error; err != nil {
+				return err
+			}
+		}
+		next, err := nextRouteOrder(tx, p.ID)
+		if err != nil {
+			return err
+		}
+		for _, r := range routes {
+			if r.Name == "" {
+				return fmt.Errorf("route name cannot be empty")
+			}
+			r.ProjectID = p.ID
+			if r.Order == 0 {
+				r.Order = next
+				next++
+			}
+			if err := tx.Create(r).Error; err != nil {
+				return fmt.Errorf("failed to create route '%s': %w", r.Name, err)
+			}
+		}
+		return nil
+	})
 }
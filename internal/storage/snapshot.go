@@ -0,0 +1,27 @@
+package storage
+
+import (
+	"github.com/raworiginal/goapi/internal/snapshot"
+	"gorm.io/gorm/clause"
+)
+
+// SaveSnapshot stores a route's snapshot, replacing any earlier one
+func SaveSnapshot(s *snapshot.Snapshot) error {
+	return DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "route_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"project_id", "body", "date_updated"}),
+	}).Create(s).Error
+}
+
+// ListSnapshotsByProject retrieves a project's snapshots keyed by route ID
+func ListSnapshotsByProject(projectID uint) (map[uint]*snapshot.Snapshot, error) {
+	var snapshots []*snapshot.Snapshot
+	if err := DB.Where("project_id = ?", projectID).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	byRoute := make(map[uint]*snapshot.Snapshot, len(snapshots))
+	for _, s := range snapshots {
+		byRoute[s.RouteID] = s
+	}
+	return byRoute, nil
+}
@@ -5,29 +5,71 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/raworiginal/goapi/internal/auth"
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/history"
 	"github.com/raworiginal/goapi/internal/project"
 	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/snapshot"
+	"github.com/raworiginal/goapi/internal/suite"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB // Global instance of the SQLite database
 
-// InitDB initializes the SQLite database and creates tables
-func InitDB() error {
+const (
+	EnvVar       = "GOAPI_DB" // database file overriding the workspace and default locations
+	WorkspaceDir = ".goapi"   // per-repository workspace holding its own database
+	dbFile       = "goapi.db"
+)
+
+// FindWorkspace returns the closest .goapi directory in dir or one of its
+// parents, or "" if there is none
+func FindWorkspace(dir string) string {
+	for {
+		candidate := filepath.Join(dir, WorkspaceDir)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ResolvePath returns the database file to use: path if set, else $GOAPI_DB, else
+// the database of the workspace enclosing the working directory, else
+// ~/.config/goapi/goapi.db
+func ResolvePath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		return env, nil
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if workspace := FindWorkspace(wd); workspace != "" {
+			return filepath.Join(workspace, dbFile), nil
+		}
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return "", err
 	}
+	return filepath.Join(homeDir, ".config", "goapi", dbFile), nil
+}
 
-	dbDir := filepath.Join(homeDir, ".config", "goapi")
-
-	if err := os.MkdirAll(dbDir, 0o755); err != nil {
+// InitDB opens the SQLite database at dbPath, creating it and its directory if
+// needed, and creates tables
+func InitDB(dbPath string) error {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0o755); err != nil {
 		return err
 	}
 
-	dbPath := filepath.Join(dbDir, "goapi.db")
-
+	var err error
 	DB, err = gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
 	if err != nil {
 		return err
@@ -38,5 +80,20 @@ func InitDB() error {
 	if err := DB.AutoMigrate(&route.Route{}); err != nil {
 		return err
 	}
+	if err := DB.AutoMigrate(&auth.Profile{}); err != nil {
+		return err
+	}
+	if err := DB.AutoMigrate(&environment.Environment{}); err != nil {
+		return err
+	}
+	if err := DB.AutoMigrate(&history.Run{}, &history.Result{}); err != nil {
+		return err
+	}
+	if err := DB.AutoMigrate(&suite.Suite{}, &suite.Step{}); err != nil {
+		return err
+	}
+	if err := DB.AutoMigrate(&snapshot.Snapshot{}); err != nil {
+		return err
+	}
 	return nil
 }
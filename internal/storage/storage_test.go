@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "repo", "src", "pkg")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", root)
+	t.Setenv(EnvVar, "")
+	t.Chdir(nested)
+
+	home := filepath.Join(root, ".config", "goapi", "goapi.db")
+	if path, _ := ResolvePath(""); path != home {
+		t.Errorf("expected %s without a workspace, got %s", home, path)
+	}
+
+	workspace := filepath.Join(root, "repo", WorkspaceDir)
+	if err := os.Mkdir(workspace, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if path, _ := ResolvePath(""); path != filepath.Join(workspace, "goapi.db") {
+		t.Errorf("expected the enclosing workspace, got %s", path)
+	}
+
+	t.Setenv(EnvVar, "/tmp/ci.db")
+	if path, _ := ResolvePath(""); path != "/tmp/ci.db" {
+		t.Errorf("expected %s to win over the workspace, got %s", EnvVar, path)
+	}
+	if path, _ := ResolvePath("custom.db"); path != "custom.db" {
+		t.Errorf("expected the flag to win, got %s", path)
+	}
+}
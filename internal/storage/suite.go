@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/raworiginal/goapi/internal/suite"
+	"gorm.io/gorm"
+)
+
+// CreateSuite adds a new, empty suite to a project
+func CreateSuite(s *suite.Suite) error {
+	if s.Name == "" {
+		return fmt.Errorf("suite name cannot be empty")
+	}
+	return DB.Create(s).Error
+}
+
+// ListSuitesByProject retrieves all suites of a project with their steps
+func ListSuitesByProject(projectID uint) ([]*suite.Suite, error) {
+	var suites []*suite.Suite
+	if err := DB.Preload("Steps", orderSteps).Where("project_id = ?", projectID).Order("name").Find(&suites).Error; err != nil {
+		return nil, err
+	}
+	return suites, nil
+}
+
+// GetSuiteByName retrieves a suite by project ID and name with its steps in order
+func GetSuiteByName(projectID uint, name string) (*suite.Suite, error) {
+	var s suite.Suite
+	if err := DB.Preload("Steps", orderSteps).Where("name = ? AND project_id = ?", name, projectID).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func orderSteps(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// AddStep inserts a step into its suite. A position of 0 appends it; otherwise the
+// steps at and after that position move down one place.
+func AddStep(step *suite.Step) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&suite.Step{}).Where("suite_id = ?", step.SuiteID).Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		if step.Position <= 0 || step.Position > last {
+			step.Position = last + 1
+		} else if err := tx.Model(&suite.Step{}).Where("suite_id = ? AND position >= ?", step.SuiteID, step.Position).
+			Update("position", gorm.Expr("position + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Create(step).Error
+	})
+}
+
+// DeleteSuite removes a suite and its steps
+func DeleteSuite(id uint) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("suite_id = ?", id).Delete(&suite.Step{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", id).Delete(&suite.Suite{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("suite not found: suite %v", id)
+		}
+		return nil
+	})
+}
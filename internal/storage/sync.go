@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/raworiginal/goapi/internal/environment"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/snapshot"
+	"gorm.io/gorm"
+)
+
+// SyncProject makes the stored project match p, envs and routes in a single
+// transaction. The project is created if it has no ID yet. Environments and routes
+// are matched by name, so existing ones keep their IDs, snapshots and history;
+// stored ones that are not listed are deleted.
+func SyncProject(p *project.Project, envs []*environment.Environment, routes []*route.Route) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if p.ID == 0 {
+			if err := tx.Create(p).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Model(p).Select("base_url", "description").Updates(p).Error; err != nil {
+			return err
+		}
+
+		var storedEnvs []*environment.Environment
+		if err := tx.Where("project_id = ?", p.ID).Find(&storedEnvs).Error; err != nil {
+			return err
+		}
+		for _, e := range envs {
+			e.ProjectID = p.ID
+			if err := save(tx, e, findByName(storedEnvs, e.Name, func(e *environment.Environment) (string, uint) { return e.Name, e.ID })); err != nil {
+				return fmt.Errorf("failed to save environment '%s': %w", e.Name, err)
+			}
+		}
+		for _, stored := range storedEnvs {
+			if !slices.ContainsFunc(envs, func(e *environment.Environment) bool { return e.Name == stored.Name }) {
+				if err := tx.Delete(stored).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var storedRoutes []*route.Route
+		if err := tx.Where("project_id = ?", p.ID).Find(&storedRoutes).Error; err != nil {
+			return err
+		}
+		for i, r := range routes {
+			r.ProjectID = p.ID
+			r.Order = i + 1
+			if err := save(tx, r, findByName(storedRoutes, r.Name, func(r *route.Route) (string, uint) { return r.Name, r.ID })); err != nil {
+				return fmt.Errorf("failed to save route '%s': %w", r.Name, err)
+			}
+		}
+		for _, stored := range storedRoutes {
+			if slices.ContainsFunc(routes, func(r *route.Route) bool { return r.Name == stored.Name }) {
+				continue
+			}
+			if err := tx.Delete(stored).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("route_id = ?", stored.ID).Delete(&snapshot.Snapshot{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// findByName returns the ID of the stored item with name, or 0
+func findByName[T any](stored []T, name string, key func(T) (string, uint)) uint {
+	for _, s := range stored {
+		if n, id := key(s); n == name {
+			return id
+		}
+	}
+	return 0
+}
+
+// save creates model when id is 0, otherwise overwrites every column of the stored
+// row with that id, including zero values
+func save(tx *gorm.DB, model any, id uint) error {
+	if id == 0 {
+		return tx.Create(model).Error
+	}
+	return tx.Model(model).Where("id = ?", id).Select("*").Omit("id", "date_created").Updates(model).Error
+}
@@ -0,0 +1,87 @@
+// Package suite models ordered, multi-step scenarios built from a project's routes
+package suite
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+// Suite is a named scenario: routes run in step order
+type Suite struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ProjectID   uint      `gorm:"uniqueIndex:idx_project_suite_name" json:"project_id"`
+	Name        string    `gorm:"uniqueIndex:idx_project_suite_name" json:"name"`
+	Description string    `json:"description"`
+	Steps       []Step    `gorm:"foreignKey:SuiteID" json:"steps"`
+	DateCreated time.Time `gorm:"autoCreateTime" json:"date_created"`
+}
+
+// Step runs one route, optionally overriding its path parameters, body and expectations
+type Step struct {
+	ID       uint               `gorm:"primaryKey" json:"id"`
+	SuiteID  uint               `gorm:"index" json:"suite_id"`
+	Position int                `json:"position"`
+	RouteID  uint               `json:"route_id"`
+	Name     string             `json:"name,omitempty"`                          // label for results, defaults to the route name
+	Params   map[string]string  `gorm:"serializer:json" json:"params,omitempty"` // path parameter values
+	Body     *string            `json:"body,omitempty"`                          // nil keeps the route's body
+	Expect   assert.Expectation `gorm:"serializer:json" json:"expect"`           // zero keeps the route's expectations
+}
+
+func (Step) TableName() string {
+	return "suite_steps"
+}
+
+// Validate checks the step's overrides against the route it references
+func (s *Step) Validate(r *route.Route) error {
+	for name := range s.Params {
+		if !slices.ContainsFunc(r.Params, func(p route.Param) bool { return p.Name == name }) {
+			return fmt.Errorf("route '%s' has no path parameter '%s'", r.Name, name)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of r with the step's overrides applied
+func (s *Step) Apply(r *route.Route) *route.Route {
+	applied := *r
+	if s.Name != "" {
+		applied.Name = s.Name
+	}
+	if len(s.Params) > 0 {
+		applied.Params = slices.Clone(r.Params)
+		for i, p := range applied.Params {
+			if value, ok := s.Params[p.Name]; ok {
+				applied.Params[i].Default = value
+			}
+		}
+	}
+	if s.Body != nil {
+		applied.Body = *s.Body
+	}
+	if !s.Expect.IsZero() {
+		applied.Expect = s.Expect
+	}
+	return &applied
+}
+
+// Overrides summarizes what the step changes about its route
+func (s *Step) Overrides() string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(s.Params)) {
+		parts = append(parts, name+"="+s.Params[name])
+	}
+	if s.Body != nil {
+		parts = append(parts, "body")
+	}
+	if !s.Expect.IsZero() {
+		parts = append(parts, "expectations")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,66 @@
+package suite
+
+import (
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/route"
+)
+
+func newRoute() *route.Route {
+	return &route.Route{
+		Name:   "Get Item",
+		Method: route.GET,
+		Path:   "/items/{id}",
+		Params: []route.Param{{Name: "id", Default: "1"}},
+		Body:   "original",
+		Expect: assert.Expectation{Status: "200"},
+	}
+}
+
+func TestApply(t *testing.T) {
+	// Arrange
+	r := newRoute()
+	body := ""
+	step := &Step{
+		Name:   "missing item",
+		Params: map[string]string{"id": "404"},
+		Body:   &body,
+		Expect: assert.Expectation{Status: "404"},
+	}
+
+	// Act
+	applied := step.Apply(r)
+
+	// Assert
+	if applied.Name != "missing item" || applied.Params[0].Default != "404" || applied.Body != "" || applied.Expect.Status != "404" {
+		t.Errorf("overrides not applied: %+v", applied)
+	}
+	if r.Params[0].Default != "1" || r.Body != "original" || r.Name != "Get Item" {
+		t.Errorf("expected route to be left unchanged, got %+v", r)
+	}
+	if got := step.Overrides(); got != "id=404, body, expectations" {
+		t.Errorf("unexpected overrides summary: %s", got)
+	}
+}
+
+func TestApplyWithoutOverrides(t *testing.T) {
+	r := newRoute()
+
+	applied := (&Step{}).Apply(r)
+
+	if applied.Name != r.Name || applied.Body != r.Body || applied.Expect.Status != "200" || applied.Params[0].Default != "1" {
+		t.Errorf("expected route values to be kept, got %+v", applied)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	r := newRoute()
+
+	if err := (&Step{Params: map[string]string{"id": "2"}}).Validate(r); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := (&Step{Params: map[string]string{"slug": "x"}}).Validate(r); err == nil {
+		t.Errorf("expected error for unknown parameter, got nil")
+	}
+}
@@ -0,0 +1,25 @@
+// Package text shortens strings for messages and stored bodies without splitting
+// UTF-8 characters
+package text
+
+import "unicode/utf8"
+
+// Cut returns the longest prefix of s that is at most n bytes long and ends on a
+// character boundary
+func Cut(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// Shorten returns s, or if it is longer than n bytes, a prefix ending in "..." that fits in n bytes
+func Shorten(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return Cut(s, n-3) + "..."
+}
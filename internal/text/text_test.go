@@ -0,0 +1,35 @@
+package text
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCut(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"}, // é is two bytes
+		{"héllo", 3, "hé"},
+		{"日本", 2, ""},
+	}
+	for _, tt := range tests {
+		if got := Cut(tt.s, tt.n); got != tt.want {
+			t.Errorf("Cut(%q, %d): expected %q, got %q", tt.s, tt.n, tt.want, got)
+		}
+	}
+}
+
+func TestShorten(t *testing.T) {
+	got := Shorten("ab日本語", 8)
+	if got != "ab日..." || !utf8.ValidString(got) || len(got) > 8 {
+		t.Errorf("expected a valid prefix with an ellipsis, got %q", got)
+	}
+	if Shorten("short", 8) != "short" {
+		t.Errorf("expected short strings unchanged")
+	}
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/storage"
+)
+
+const (
+	fieldName = iota
+	fieldMethod
+	fieldPath
+	fieldDescription
+	fieldContentType
+	fieldBody
+)
+
+var fieldLabels = []string{"Name", "Method", "Path", "Description", "Content-Type", "Body"}
+
+// routeForm edits the basic fields of a route inline
+type routeForm struct {
+	route   *route.Route
+	inputs  []textinput.Model
+	focused int
+}
+
+func newRouteForm(r *route.Route) *routeForm {
+	values := []string{r.Name, string(r.Method), r.Path, r.Description, r.ContentType, r.Body}
+	f := &routeForm{route: r}
+	for _, value := range values {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 0
+		input.Width = 60
+		input.SetValue(value)
+		f.inputs = append(f.inputs, input)
+	}
+	return f
+}
+
+func (f *routeForm) focus() tea.Cmd {
+	for i := range f.inputs {
+		f.inputs[i].Blur()
+	}
+	return f.inputs[f.focused].Focus()
+}
+
+func (f *routeForm) update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "tab", "down", "enter":
+		f.focused = (f.focused + 1) % len(f.inputs)
+		return f.focus()
+	case "shift+tab", "up":
+		f.focused = (f.focused - 1 + len(f.inputs)) % len(f.inputs)
+		return f.focus()
+	}
+	var cmd tea.Cmd
+	f.inputs[f.focused], cmd = f.inputs[f.focused].Update(msg)
+	return cmd
+}
+
+func (f *routeForm) view() string {
+	var b strings.Builder
+	for i, input := range f.inputs {
+		cursor := "  "
+		if i == f.focused {
+			cursor = "> "
+		}
+		fmt.Fprintf(&b, "%s%-13s %s\n", cursor, fieldLabels[i]+":", input.View())
+	}
+	return b.String()
+}
+
+// save validates the form and writes the changed fields to storage
+func (f *routeForm) save() tea.Cmd {
+	r := f.route
+	value := func(field int) string { return strings.TrimSpace(f.inputs[field].Value()) }
+	name, path := value(fieldName), value(fieldPath)
+	methodStr, description := value(fieldMethod), value(fieldDescription)
+	contentType, body := value(fieldContentType), f.inputs[fieldBody].Value()
+	return func() tea.Msg {
+		if name == "" || path == "" {
+			return errMsg{fmt.Errorf("name and path are required")}
+		}
+		method, err := route.ParseHTTPMethod(methodStr)
+		if err != nil {
+			return errMsg{err}
+		}
+		updates := &route.UpdateRouteInput{
+			Name:        &name,
+			Method:      &method,
+			Path:        &path,
+			Description: &description,
+			ContentType: &contentType,
+			Body:        &body,
+		}
+		if path != r.Path {
+			params, err := route.RebuildParams(r.Params, path, nil)
+			if err != nil {
+				return errMsg{fmt.Errorf("invalid path '%s': %w", path, err)}
+			}
+			updates.Params = &params
+		}
+		if err := storage.UpdateRoute(r.ID, updates); err != nil {
+			return errMsg{fmt.Errorf("failed to update route: %w", err)}
+		}
+		updated, err := storage.GetRoute(r.ID)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to reload route: %w", err)}
+		}
+		return savedMsg{updated}
+	}
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/raworiginal/goapi/internal/runner"
+)
+
+// renderResult formats a route's result for the result pane
+func renderResult(r runner.TestResult, width int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", r.Method, r.Path)
+	if r.Error != "" {
+		b.WriteString(errorStyle.Render("Error: "+r.Error) + "\n")
+		return b.String()
+	}
+
+	outcome := passStyle.Render("PASS")
+	if !r.Passed() {
+		outcome = errorStyle.Render("FAIL")
+	}
+	fmt.Fprintf(&b, "Status: %d   Duration: %v   Result: %s\n", r.StatusCode, r.Duration, outcome)
+
+	if len(r.Assertions) > 0 {
+		b.WriteString("\n" + headerStyle.Render("Assertions") + "\n")
+		for _, a := range r.Assertions {
+			if a.Passed {
+				b.WriteString(passStyle.Render("  ✓ "+a.Name) + "\n")
+			} else {
+				b.WriteString(errorStyle.Render(fmt.Sprintf("  ✗ %s: %s", a.Name, a.Message)) + "\n")
+			}
+		}
+	}
+
+	if len(r.Extracted) > 0 {
+		b.WriteString("\n" + headerStyle.Render("Extracted") + "\n")
+		for _, name := range slices.Sorted(maps.Keys(r.Extracted)) {
+			fmt.Fprintf(&b, "  %s = %s\n", name, r.Extracted[name])
+		}
+	}
+
+	b.WriteString("\n" + headerStyle.Render("Headers") + "\n")
+	for _, name := range slices.Sorted(maps.Keys(r.ResponseHeaders)) {
+		fmt.Fprintf(&b, "  %s: %s\n", name, strings.Join(r.ResponseHeaders[name], ", "))
+	}
+
+	b.WriteString("\n" + headerStyle.Render("Body") + "\n")
+	b.WriteString(lipgloss.NewStyle().Width(width).Render(prettyBody(r.ResponseBody)))
+	return b.String()
+}
+
+// prettyBody indents JSON bodies and returns anything else unchanged
+func prettyBody(body string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return buf.String()
+}
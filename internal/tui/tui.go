@@ -0,0 +1,312 @@
+// Package tui is the interactive terminal interface for browsing projects and running routes
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/raworiginal/goapi/internal/project"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/runner"
+	"github.com/raworiginal/goapi/internal/storage"
+)
+
+// RunnerFactory creates the runner used to execute a project's routes
+type RunnerFactory func(p *project.Project) (*runner.Runner, error)
+
+type view int
+
+const (
+	projectsView view = iota
+	routesView
+	resultView
+	editView
+)
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	helpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	errorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	passStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	headerStyle = lipgloss.NewStyle().Bold(true)
+)
+
+type Model struct {
+	view      view
+	projects  list.Model
+	routes    list.Model
+	result    viewport.Model
+	form      *routeForm
+	project   *project.Project
+	route     *route.Route
+	newRunner RunnerFactory
+	status    string
+	err       error
+	running   bool
+	width     int
+	height    int
+}
+
+type projectsLoadedMsg struct{ projects []*project.Project }
+
+type routesLoadedMsg struct{ routes []*route.Route }
+
+type resultMsg struct{ result runner.TestResult }
+
+type savedMsg struct{ route *route.Route }
+
+type errMsg struct{ err error }
+
+type projectItem struct{ p *project.Project }
+
+func (i projectItem) Title() string       { return i.p.Name }
+func (i projectItem) Description() string { return i.p.BaseURL }
+func (i projectItem) FilterValue() string { return i.p.Name }
+
+type routeItem struct{ r *route.Route }
+
+func (i routeItem) Title() string       { return fmt.Sprintf("%-6s %s", i.r.Method, i.r.Name) }
+func (i routeItem) Description() string { return i.r.Path }
+func (i routeItem) FilterValue() string { return i.r.Name }
+
+// New creates the TUI model
+func New(newRunner RunnerFactory) Model {
+	projects := newList("Projects")
+	routes := newList("Routes")
+	return Model{
+		projects:  projects,
+		routes:    routes,
+		result:    viewport.New(0, 0),
+		newRunner: newRunner,
+	}
+}
+
+// newList creates a list whose help and quit keys are handled by the model
+func newList(title string) list.Model {
+	l := list.New(nil, list.NewDefaultDelegate(), 0, 0)
+	l.Title = title
+	l.SetShowHelp(false)
+	l.KeyMap.Quit.SetEnabled(false)
+	return l
+}
+
+// Run starts the TUI and blocks until the user quits
+func Run(newRunner RunnerFactory) error {
+	_, err := tea.NewProgram(New(newRunner), tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m Model) Init() tea.Cmd {
+	return loadProjects
+}
+
+func loadProjects() tea.Msg {
+	projects, err := storage.ListProjects()
+	if err != nil {
+		return errMsg{fmt.Errorf("failed to list projects: %w", err)}
+	}
+	return projectsLoadedMsg{projects}
+}
+
+func loadRoutes(projectID uint) tea.Cmd {
+	return func() tea.Msg {
+		routes, err := storage.ListRoutesByProject(projectID)
+		if err != nil {
+			return errMsg{fmt.Errorf("failed to list routes: %w", err)}
+		}
+		return routesLoadedMsg{routes}
+	}
+}
+
+func (m Model) runRoute() tea.Cmd {
+	p, r, newRunner := m.project, m.route, m.newRunner
+	return func() tea.Msg {
+		run, err := newRunner(p)
+		if err != nil {
+			return errMsg{err}
+		}
+		return resultMsg{run.Run(r)}
+	}
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.projects.SetSize(msg.Width, msg.Height-2)
+		m.routes.SetSize(msg.Width, msg.Height-2)
+		m.result.Width, m.result.Height = msg.Width, msg.Height-3
+		return m, nil
+	case projectsLoadedMsg:
+		items := make([]list.Item, 0, len(msg.projects))
+		for _, p := range msg.projects {
+			items = append(items, projectItem{p})
+		}
+		return m, m.projects.SetItems(items)
+	case routesLoadedMsg:
+		items := make([]list.Item, 0, len(msg.routes))
+		for _, r := range msg.routes {
+			items = append(items, routeItem{r})
+		}
+		m.routes.Title = "Routes in " + m.project.Name
+		return m, m.routes.SetItems(items)
+	case resultMsg:
+		m.running = false
+		m.status = ""
+		m.result.SetContent(renderResult(msg.result, m.width))
+		m.result.GotoTop()
+		m.view = resultView
+		return m, nil
+	case savedMsg:
+		m.route = msg.route
+		m.form = nil
+		m.view = routesView
+		m.status = fmt.Sprintf("Saved route '%s'", msg.route.Name)
+		return m, loadRoutes(m.project.ID)
+	case errMsg:
+		m.running = false
+		m.err = msg.err
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		m.err = nil
+		switch m.view {
+		case projectsView:
+			return m.updateProjects(msg)
+		case routesView:
+			return m.updateRoutes(msg)
+		case resultView:
+			return m.updateResult(msg)
+		case editView:
+			return m.updateEdit(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateProjects(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.projects.FilterState() != list.Filtering {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "enter":
+			item, ok := m.projects.SelectedItem().(projectItem)
+			if !ok {
+				return m, nil
+			}
+			m.project = item.p
+			m.routes.ResetFilter()
+			m.routes.Select(0)
+			m.view = routesView
+			return m, loadRoutes(item.p.ID)
+		}
+	}
+	var cmd tea.Cmd
+	m.projects, cmd = m.projects.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateRoutes(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.routes.FilterState() != list.Filtering {
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			if m.routes.FilterState() == list.Unfiltered {
+				m.view = projectsView
+				m.status = ""
+				return m, nil
+			}
+		case "enter", "r":
+			item, ok := m.routes.SelectedItem().(routeItem)
+			if !ok || m.running {
+				return m, nil
+			}
+			m.route = item.r
+			m.running = true
+			m.status = fmt.Sprintf("Running %s %s...", item.r.Method, item.r.Path)
+			return m, m.runRoute()
+		case "e":
+			item, ok := m.routes.SelectedItem().(routeItem)
+			if !ok {
+				return m, nil
+			}
+			m.route = item.r
+			m.form = newRouteForm(item.r)
+			m.view = editView
+			m.status = ""
+			return m, m.form.focus()
+		}
+	}
+	var cmd tea.Cmd
+	m.routes, cmd = m.routes.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateResult(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "esc", "backspace":
+		m.view = routesView
+		return m, nil
+	case "r":
+		if m.running {
+			return m, nil
+		}
+		m.running = true
+		m.status = fmt.Sprintf("Running %s %s...", m.route.Method, m.route.Path)
+		return m, m.runRoute()
+	case "e":
+		m.form = newRouteForm(m.route)
+		m.view = editView
+		return m, m.form.focus()
+	}
+	var cmd tea.Cmd
+	m.result, cmd = m.result.Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.form = nil
+		m.view = routesView
+		m.status = "Edit cancelled"
+		return m, nil
+	case "ctrl+s":
+		return m, m.form.save()
+	}
+	cmd := m.form.update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	var body, help string
+	switch m.view {
+	case projectsView:
+		body = m.projects.View()
+		help = "enter: open • /: filter • q: quit"
+	case routesView:
+		body = m.routes.View()
+		help = "enter/r: run • e: edit • /: filter • esc: projects • q: quit"
+	case resultView:
+		body = titleStyle.Render(m.route.Name) + "\n" + m.result.View()
+		help = "↑/↓: scroll • r: run again • e: edit • esc: routes • q: quit"
+	case editView:
+		body = titleStyle.Render("Edit "+m.route.Name) + "\n\n" + m.form.view()
+		help = "tab/shift+tab: move • ctrl+s: save • esc: cancel"
+	}
+	footer := helpStyle.Render(help)
+	if m.err != nil {
+		footer = errorStyle.Render("Error: "+m.err.Error()) + "\n" + footer
+	} else if m.status != "" {
+		footer = m.status + "\n" + footer
+	}
+	return body + "\n" + footer
+}
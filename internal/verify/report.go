@@ -0,0 +1,131 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raworiginal/goapi/internal/runner"
+)
+
+// Report is the outcome of verifying a project against a spec
+type Report struct {
+	Project    string              `json:"project"`
+	Spec       string              `json:"spec"`
+	StartedAt  time.Time           `json:"started_at"`
+	Findings   []Finding           `json:"findings"`
+	Operations []OperationCoverage `json:"operations"`
+	Results    []runner.TestResult `json:"results,omitempty"`
+	Covered    int                 `json:"covered"` // operations exercised by at least one route
+	Failed     int                 `json:"failed"`  // routes that failed
+}
+
+// NewReport builds a report from a comparison and the results of running the routes,
+// which may be empty when the routes were not run
+func NewReport(project, spec string, startedAt time.Time, c *Comparison, results []runner.TestResult) *Report {
+	r := &Report{
+		Project:    project,
+		Spec:       spec,
+		StartedAt:  startedAt,
+		Findings:   c.Findings,
+		Operations: c.Coverage(results),
+		Results:    results,
+	}
+	for _, oc := range r.Operations {
+		if oc.Exercised {
+			r.Covered++
+		}
+	}
+	for _, res := range results {
+		if !res.Passed() {
+			r.Failed++
+		}
+	}
+	return r
+}
+
+// Drift counts routes that are missing from the spec or use a method it does not declare
+func (r *Report) Drift() int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Kind != NoRoute {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteJSON writes the report as an indented JSON document
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTable prints the drift findings, a coverage table of the spec's operations,
+// the details of failed routes and a summary line
+func (r *Report) WriteTable(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if len(r.Findings) > 0 {
+		if _, err := fmt.Fprintln(w, "Drift\tMethod\tPath\tRoute\tDetail"); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+		for _, f := range r.Findings {
+			route := f.Route
+			if route == "" {
+				route = "-"
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Kind, f.Method, f.Path, route, f.Message); err != nil {
+				return fmt.Errorf("failed to write table line: %w", err)
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "Operation\tName\tRoutes\tResult"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	for _, oc := range r.Operations {
+		routes, outcome := "-", "NOT COVERED"
+		if len(oc.Routes) > 0 {
+			routes = strings.Join(oc.Routes, ", ")
+		}
+		switch {
+		case oc.Exercised && oc.Passed:
+			outcome = "PASS"
+		case oc.Exercised:
+			outcome = "FAIL"
+		case len(oc.Routes) > 0 && len(r.Results) == 0:
+			outcome = "MATCHED"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s\t%s\t%s\t%s\n", oc.Method, oc.Path, oc.Name, routes, outcome); err != nil {
+			return fmt.Errorf("failed to write table line: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to flush table: %w", err)
+	}
+
+	for _, res := range r.Results {
+		for _, line := range res.Failures() {
+			if _, err := fmt.Fprintf(out, "%s: %s\n", res.RouteName, line); err != nil {
+				return err
+			}
+		}
+	}
+	pct := 0.0
+	if len(r.Operations) > 0 {
+		pct = float64(r.Covered) / float64(len(r.Operations)) * 100
+	}
+	summary := fmt.Sprintf("\nCoverage: %d of %d operations exercised (%.0f%%), %d drift finding(s)", r.Covered, len(r.Operations), pct, r.Drift())
+	if len(r.Results) > 0 {
+		summary += fmt.Sprintf(", %d of %d routes failed", r.Failed, len(r.Results))
+	}
+	_, err := fmt.Fprintln(out, summary)
+	return err
+}
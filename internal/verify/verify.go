@@ -0,0 +1,224 @@
+// Package verify compares a project's routes with an OpenAPI document and checks
+// responses against the operations the routes exercise
+package verify
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/assert"
+	"github.com/raworiginal/goapi/internal/openapi"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/runner"
+	"github.com/raworiginal/goapi/internal/schema"
+)
+
+// Kind is a type of drift between the routes and the spec
+type Kind string
+
+const (
+	NotInSpec      Kind = "not-in-spec"     // the route's path is not in the spec
+	MethodMismatch Kind = "method-mismatch" // the route's path is in the spec, but not with its method
+	NoRoute        Kind = "no-route"        // no route exercises the operation
+)
+
+// Finding is one difference between the routes and the spec
+type Finding struct {
+	Kind    Kind   `json:"kind"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Route   string `json:"route,omitempty"`
+	Message string `json:"message"`
+}
+
+// Comparison is the result of matching routes to the spec's operations
+type Comparison struct {
+	Doc        *openapi.Document
+	Operations map[uint]*openapi.Operation // matched operation by route ID
+	Findings   []Finding
+	routes     []*route.Route
+}
+
+// PathPrefix returns the part of the spec's server path that route paths include
+// because the project's base URL stops short of it, e.g. /v1 for a project at
+// https://api.example.com and a server at https://api.example.com/v1
+func PathPrefix(baseURL string, servers []string) string {
+	if len(servers) == 0 {
+		return ""
+	}
+	base, err1 := url.Parse(baseURL)
+	server, err2 := url.Parse(servers[0])
+	if err1 != nil || err2 != nil {
+		return ""
+	}
+	basePath, serverPath := strings.TrimSuffix(base.Path, "/"), strings.TrimSuffix(server.Path, "/")
+	if rest, ok := strings.CutPrefix(serverPath, basePath); ok && strings.HasPrefix(rest, "/") {
+		return rest
+	}
+	return ""
+}
+
+// Compare matches each route to the operation with the same method and path. Spec
+// parameters match any path segment; fixed segments win over parameters. prefix is
+// removed from route paths first.
+func Compare(doc *openapi.Document, routes []*route.Route, prefix string) *Comparison {
+	c := &Comparison{Doc: doc, Operations: make(map[uint]*openapi.Operation), routes: routes}
+	covered := make(map[*openapi.Operation]bool)
+	for _, r := range routes {
+		path, _, _ := strings.Cut(r.Path, "?")
+		path = strings.TrimPrefix(path, prefix)
+		candidates := c.matchPath(path)
+		if len(candidates) == 0 {
+			c.Findings = append(c.Findings, Finding{Kind: NotInSpec, Method: string(r.Method), Path: r.Path, Route: r.Name, Message: "path is not in the spec"})
+			continue
+		}
+		i := slices.IndexFunc(candidates, func(op *openapi.Operation) bool { return op.Method == string(r.Method) })
+		if i < 0 {
+			var methods []string
+			for _, op := range candidates {
+				methods = append(methods, op.Method)
+			}
+			c.Findings = append(c.Findings, Finding{
+				Kind:    MethodMismatch,
+				Method:  string(r.Method),
+				Path:    r.Path,
+				Route:   r.Name,
+				Message: fmt.Sprintf("spec declares %s only for %s", candidates[0].Path, strings.Join(methods, ", ")),
+			})
+			continue
+		}
+		c.Operations[r.ID] = candidates[i]
+		covered[candidates[i]] = true
+	}
+	for i := range doc.Operations {
+		op := &doc.Operations[i]
+		if !covered[op] {
+			c.Findings = append(c.Findings, Finding{Kind: NoRoute, Method: op.Method, Path: op.Path, Message: "no route exercises " + op.Name()})
+		}
+	}
+	return c
+}
+
+// matchPath returns the operations on the spec path that best matches path
+func (c *Comparison) matchPath(path string) []*openapi.Operation {
+	parts := split(path)
+	best, bestScore := "", -1
+	for _, op := range c.Doc.Operations {
+		if score, ok := matchSegments(split(op.Path), parts); ok && score > bestScore {
+			best, bestScore = op.Path, score
+		}
+	}
+	var ops []*openapi.Operation
+	for i := range c.Doc.Operations {
+		if bestScore >= 0 && c.Doc.Operations[i].Path == best {
+			ops = append(ops, &c.Doc.Operations[i])
+		}
+	}
+	return ops
+}
+
+func split(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// matchSegments reports whether a route path fits a spec path and how many
+// segments matched literally
+func matchSegments(spec, parts []string) (int, bool) {
+	if len(spec) != len(parts) {
+		return 0, false
+	}
+	score := 0
+	for i, seg := range spec {
+		switch {
+		case isParam(seg):
+		case seg == parts[i]:
+			score++
+		default:
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+func isParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && !strings.HasPrefix(segment, "{{")
+}
+
+// Check is a runner.Check for matched routes: the response status must be declared
+// by the operation and the body must satisfy the declared schema
+func (c *Comparison) Check(r *route.Route, resp *api.Response) []assert.Result {
+	op := c.Operations[r.ID]
+	if op == nil {
+		return nil
+	}
+	declared := op.Response(resp.StatusCode)
+	status := assert.Result{Name: "spec status", Passed: declared != nil}
+	if declared == nil {
+		status.Message = fmt.Sprintf("status %d is not declared for %s %s (declared: %s)", resp.StatusCode, op.Method, op.Path, strings.Join(op.Statuses(), ", "))
+		return []assert.Result{status}
+	}
+	results := []assert.Result{status}
+	if declared.Schema == nil {
+		return results
+	}
+	check := assert.Result{Name: "spec schema"}
+	violations, err := schema.New(c.Doc.Raw(), declared.Schema).ValidateBytes(resp.Body)
+	switch {
+	case err != nil:
+		check.Message = err.Error()
+	case len(violations) == 0:
+		check.Passed = true
+	default:
+		lines := []string{fmt.Sprintf("%d violation(s) of the %s response schema", len(violations), declared.Status)}
+		for _, v := range violations {
+			lines = append(lines, "  "+v.String())
+		}
+		check.Message = strings.Join(lines, "\n")
+	}
+	return append(results, check)
+}
+
+// OperationCoverage records which routes exercised an operation and how they fared
+type OperationCoverage struct {
+	Method    string   `json:"method"`
+	Path      string   `json:"path"`
+	Name      string   `json:"name"`
+	Routes    []string `json:"routes,omitempty"`
+	Exercised bool     `json:"exercised"` // at least one route got a response
+	Passed    bool     `json:"passed"`    // every route that ran passed
+}
+
+// Coverage summarizes, for each operation in the spec, the results of the routes matched to it
+func (c *Comparison) Coverage(results []runner.TestResult) []OperationCoverage {
+	coverage := make([]OperationCoverage, len(c.Doc.Operations))
+	index := make(map[*openapi.Operation]int, len(c.Doc.Operations))
+	for i := range c.Doc.Operations {
+		op := &c.Doc.Operations[i]
+		index[op] = i
+		coverage[i] = OperationCoverage{Method: op.Method, Path: op.Path, Name: op.Name(), Passed: true}
+	}
+	for _, r := range c.routes {
+		if op := c.Operations[r.ID]; op != nil {
+			oc := &coverage[index[op]]
+			oc.Routes = append(oc.Routes, r.Name)
+		}
+	}
+	for _, res := range results {
+		op := c.Operations[res.RouteID]
+		if op == nil {
+			continue
+		}
+		oc := &coverage[index[op]]
+		if res.Error == "" {
+			oc.Exercised = true
+		}
+		oc.Passed = oc.Passed && res.Passed()
+	}
+	for i := range coverage {
+		coverage[i].Passed = coverage[i].Passed && coverage[i].Exercised
+	}
+	return coverage
+}
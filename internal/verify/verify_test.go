@@ -0,0 +1,110 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/raworiginal/goapi/internal/api"
+	"github.com/raworiginal/goapi/internal/openapi"
+	"github.com/raworiginal/goapi/internal/route"
+	"github.com/raworiginal/goapi/internal/runner"
+)
+
+const spec = `
+openapi: 3.1.0
+info: {title: Pets}
+servers: [{url: https://api.example.com/v1}]
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {type: array, items: {$ref: '#/components/schemas/Pet'}}
+  /pets/{petId}:
+    get:
+      operationId: getPet
+      responses:
+        2XX: {description: ok}
+  /pets/mine:
+    get:
+      operationId: myPets
+      responses: {"200": {description: ok}}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+`
+
+func TestCompare(t *testing.T) {
+	doc, err := openapi.Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	routes := []*route.Route{
+		{ID: 1, Name: "List", Method: route.GET, Path: "/v1/pets?limit=5"},
+		{ID: 2, Name: "Get", Method: route.GET, Path: "/v1/pets/{{pet_id}}"},
+		{ID: 3, Name: "Delete", Method: route.DELETE, Path: "/v1/pets/7"},
+		{ID: 4, Name: "Health", Method: route.GET, Path: "/v1/health"},
+	}
+
+	c := Compare(doc, routes, PathPrefix("https://api.example.com", doc.Servers))
+
+	if c.Operations[1] == nil || c.Operations[1].OperationID != "listPets" {
+		t.Errorf("expected List to match listPets, got %+v", c.Operations[1])
+	}
+	if c.Operations[2] == nil || c.Operations[2].OperationID != "getPet" {
+		t.Errorf("expected variable segment to match a spec parameter, got %+v", c.Operations[2])
+	}
+	kinds := map[string]Kind{}
+	for _, f := range c.Findings {
+		kinds[f.Method+" "+f.Path] = f.Kind
+	}
+	want := map[string]Kind{"DELETE /v1/pets/7": MethodMismatch, "GET /v1/health": NotInSpec, "GET /pets/mine": NoRoute}
+	if len(kinds) != len(want) {
+		t.Errorf("expected %d findings, got %v", len(want), kinds)
+	}
+	for key, kind := range want {
+		if kinds[key] != kind {
+			t.Errorf("%s: expected %s, got %q", key, kind, kinds[key])
+		}
+	}
+}
+
+func TestCheckAndCoverage(t *testing.T) {
+	doc, err := openapi.Parse([]byte(spec))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	list := &route.Route{ID: 1, Name: "List", Method: route.GET, Path: "/pets"}
+	get := &route.Route{ID: 2, Name: "Get", Method: route.GET, Path: "/pets/1"}
+	c := Compare(doc, []*route.Route{list, get}, "")
+
+	listResults := c.Check(list, &api.Response{StatusCode: 200, Body: []byte(`[{"name": "Rex"}, {}]`)})
+	getResults := c.Check(get, &api.Response{StatusCode: 500})
+
+	if len(listResults) != 2 || !listResults[0].Passed || listResults[1].Passed {
+		t.Errorf("expected declared status and a schema violation, got %+v", listResults)
+	}
+	if len(getResults) != 1 || getResults[0].Passed {
+		t.Errorf("expected undeclared status to fail, got %+v", getResults)
+	}
+
+	coverage := c.Coverage([]runner.TestResult{
+		{RouteID: 1, RouteName: "List", Assertions: listResults},
+		{RouteID: 2, RouteName: "Get", Error: "connection refused"},
+	})
+	byName := map[string]OperationCoverage{}
+	for _, oc := range coverage {
+		byName[oc.Name] = oc
+	}
+	if oc := byName["listPets"]; !oc.Exercised || oc.Passed {
+		t.Errorf("expected listPets exercised and failing, got %+v", oc)
+	}
+	if oc := byName["getPet"]; oc.Exercised || len(oc.Routes) != 1 {
+		t.Errorf("expected getPet matched but not exercised, got %+v", oc)
+	}
+}